@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/backtester"
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/providers/bybit"
+	"github.com/letieu/trade-bot/internal/providers/candlestore"
+	"github.com/letieu/trade-bot/internal/strategies"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+func main() {
+	var (
+		configFile = flag.String("config", "", "Path to config file (optional, defaults to trade-bot.yaml)")
+		interval   = flag.String("interval", "1h", "Time interval (1m, 5m, 15m, 30m, 1h, 4h, 1d)")
+		days       = flag.Int("days", 30, "Number of days to backtest")
+		symbolsStr = flag.String("symbols", "", "Comma-separated list of symbols (empty = all USDT symbols)")
+		save       = flag.Bool("save", true, "Save results to file")
+		output     = flag.String("output", "", "Output directory for results (defaults to backtest.resultsPath)")
+		useCache   = flag.Bool("cache", true, "Serve candles from the local store under backtest.dataPath")
+		simulate   = flag.Bool("simulate", false, "Simulate trades with stop-loss/take-profit (defaults to backtest.execution.enabled)")
+	)
+	flag.Parse()
+
+	cfg := config.Load(*configFile)
+
+	// Ctrl+C or SIGTERM cancels outstanding requests
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	start, end, err := resolveWindow(&cfg.Backtest, *days, explicit["days"])
+	if err != nil {
+		log.Fatalf("Invalid backtest window: %v", err)
+	}
+
+	saveResults := cfg.Backtest.SaveResults
+	if explicit["save"] {
+		saveResults = *save
+	}
+	resultsPath := cfg.Backtest.ResultsPath
+	if *output != "" {
+		resultsPath = *output
+	}
+
+	client := bybit.NewClient(&cfg.Bybit)
+
+	var provider types.MarketDataProvider = client
+	if *useCache {
+		provider = candlestore.NewProvider(candlestore.NewStore(cfg.Backtest.DataPath), client)
+	}
+
+	var symbols []string
+	if *symbolsStr != "" {
+		for _, s := range strings.Split(*symbolsStr, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				symbols = append(symbols, strings.ToUpper(s))
+			}
+		}
+	} else {
+		symbols, err = provider.GetSymbols(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get symbols: %v", err)
+		}
+	}
+
+	configured, err := strategies.DefaultRegistry().BuildAll(cfg.Strategies)
+	if err != nil {
+		log.Fatalf("Invalid strategies config: %v", err)
+	}
+	var strategyList []types.PatternMatcher
+	for _, s := range configured {
+		if s.RunsOn(*interval) {
+			strategyList = append(strategyList, s.Matcher)
+		}
+	}
+	if len(strategyList) == 0 {
+		log.Fatalf("No enabled strategy runs on %s", *interval)
+	}
+
+	log.Printf("Backtesting %d symbols on %s from %s to %s", len(symbols), *interval,
+		start.Format(time.RFC3339), end.Format(time.RFC3339))
+
+	bt := backtester.NewBacktester(provider, strategyList)
+	if (explicit["simulate"] && *simulate) || (!explicit["simulate"] && cfg.Backtest.Execution.Enabled) {
+		bt.Execution = &cfg.Backtest.Execution
+	}
+	results, err := bt.Run(ctx, symbols, *interval, start, end)
+	if err != nil {
+		log.Fatalf("Backtest failed: %v", err)
+	}
+
+	for _, result := range results {
+		printSummary(result)
+
+		if saveResults {
+			path, err := backtester.SaveResult(result, resultsPath)
+			if err != nil {
+				log.Printf("Failed to save result for %s: %v", result.Strategy, err)
+				continue
+			}
+			log.Printf("Saved result to %s", path)
+		}
+	}
+}
+
+// resolveWindow uses the configured start/end times unless -days was given explicitly
+func resolveWindow(cfg *config.BacktestConfig, days int, daysSet bool) (time.Time, time.Time, error) {
+	if !daysSet && cfg.StartTime != "" && cfg.EndTime != "" {
+		start, err := time.Parse(time.RFC3339, cfg.StartTime)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("backtest.startTime: %w", err)
+		}
+		end, err := time.Parse(time.RFC3339, cfg.EndTime)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("backtest.endTime: %w", err)
+		}
+		return start, end, nil
+	}
+
+	if days <= 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf("days must be positive, got %d", days)
+	}
+	end := time.Now().UTC()
+	return end.AddDate(0, 0, -days), end, nil
+}
+
+func printSummary(result *backtester.Result) {
+	fmt.Printf("\n=== %s — %s ===\n", result.Strategy, result.Interval)
+	fmt.Printf("Total signals: %d\n", result.TotalSignals)
+	fmt.Printf("Symbols with signals: %d\n", len(result.SignalsBySymbol))
+	fmt.Printf("Missing symbols: %d\n", len(result.MissingSignals))
+
+	type symbolCount struct {
+		symbol string
+		count  int
+	}
+	var counts []symbolCount
+	for symbol, count := range result.SignalsBySymbol {
+		counts = append(counts, symbolCount{symbol: symbol, count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].count != counts[j].count {
+			return counts[i].count > counts[j].count
+		}
+		return counts[i].symbol < counts[j].symbol
+	})
+
+	for i, c := range counts {
+		if i >= 10 {
+			break
+		}
+		fmt.Printf("  %-20s %d\n", c.symbol, c.count)
+	}
+
+	printOutcomeStats(result.OutcomeStats)
+	printTradeStats(result.TradeStats)
+}
+
+func printTradeStats(stats *backtester.TradeStats) {
+	if stats == nil {
+		return
+	}
+
+	fmt.Println("\nSimulated trades:")
+	fmt.Printf("  Trades: %d (won %d, lost %d), win rate %.1f%%\n", stats.Trades, stats.Wins, stats.Losses, stats.WinRate)
+	fmt.Printf("  Profit factor: %.2f, avg trade %+.2f%%, Sharpe (per trade) %.2f\n", stats.ProfitFactor, stats.AvgReturn, stats.Sharpe)
+	fmt.Printf("  Total return: %+.2f%%, max drawdown %.2f%%, final equity %.2f\n", stats.TotalReturn, stats.MaxDrawdown, stats.FinalEquity)
+}
+
+func printOutcomeStats(stats []backtester.OutcomeStats) {
+	if len(stats) == 0 {
+		return
+	}
+
+	fmt.Println("\nForward returns (direction-adjusted):")
+	for _, s := range stats {
+		fmt.Printf("  %s %s %s — %d signals, avg MFE %.2f%%, avg MAE %.2f%%\n",
+			s.Pattern, s.Interval, s.Trend, s.Count, s.AvgMaxFavorable, s.AvgMaxAdverse)
+		for _, h := range s.Horizons {
+			fmt.Printf("    +%-3d bars  hit %5.1f%%  avg %+.2f%%  (n=%d)\n", h.Bars, h.HitRate, h.AvgReturn, h.Samples)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/providers/bybit"
+	"github.com/letieu/trade-bot/internal/providers/candlestore"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+func main() {
+	var (
+		configFile   = flag.String("config", "", "Path to config file (optional, defaults to trade-bot.yaml)")
+		intervalsStr = flag.String("intervals", "", "Comma-separated intervals to fetch (defaults to bot.enabledIntervals)")
+		days         = flag.Int("days", 365, "How many days of history to backfill")
+		symbolsStr   = flag.String("symbols", "", "Comma-separated list of symbols (empty = all USDT symbols)")
+		dataPath     = flag.String("data", "", "Candle store directory (defaults to backtest.dataPath)")
+	)
+	flag.Parse()
+
+	cfg := config.Load(*configFile)
+
+	// Ctrl+C or SIGTERM stops the backfill; candles already fetched stay on disk
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	intervals := splitList(*intervalsStr)
+	if len(intervals) == 0 {
+		intervals = cfg.Bot.EnabledIntervals
+	}
+	for _, interval := range intervals {
+		if _, err := types.ParseInterval(interval); err != nil {
+			log.Fatalf("Invalid interval: %v", err)
+		}
+	}
+
+	if *days <= 0 {
+		log.Fatalf("days must be positive, got %d", *days)
+	}
+	since := time.Now().UTC().AddDate(0, 0, -*days)
+
+	dir := cfg.Backtest.DataPath
+	if *dataPath != "" {
+		dir = *dataPath
+	}
+
+	client := bybit.NewClient(&cfg.Bybit)
+	provider := candlestore.NewProvider(candlestore.NewStore(dir), client)
+
+	symbols := splitList(strings.ToUpper(*symbolsStr))
+	if len(symbols) == 0 {
+		var err error
+		symbols, err = client.GetSymbols(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get symbols: %v", err)
+		}
+	}
+
+	concurrency := cfg.Bot.MaxConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	for _, interval := range intervals {
+		log.Printf("[%s] Syncing %d symbols since %s into %s", interval, len(symbols), since.Format(time.RFC3339), dir)
+
+		var written, failed int64
+		semaphore := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+
+		for _, symbol := range symbols {
+			wg.Add(1)
+			go func(sym string) {
+				defer wg.Done()
+
+				select {
+				case semaphore <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				defer func() { <-semaphore }()
+
+				n, err := provider.Sync(ctx, sym, interval, since)
+				if err != nil {
+					log.Printf("[%s] Failed to sync %s: %v", interval, sym, err)
+					atomic.AddInt64(&failed, 1)
+					return
+				}
+				atomic.AddInt64(&written, int64(n))
+			}(symbol)
+		}
+
+		wg.Wait()
+		log.Printf("[%s] Stored %d new candles, %d symbols failed", interval, written, failed)
+
+		if ctx.Err() != nil {
+			log.Println("Fetch interrupted")
+			return
+		}
+	}
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/backtester"
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/providers/bybit"
+	"github.com/letieu/trade-bot/internal/providers/candlestore"
+	"github.com/letieu/trade-bot/internal/strategies"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+func main() {
+	var (
+		configFile   = flag.String("config", "", "Path to config file (optional, defaults to trade-bot.yaml)")
+		strategyName = flag.String("strategy", "consecutive", "Registered strategy to optimise (e.g. consecutive, reversal, engulfing)")
+		paramsStr    = flag.String("params", "minCount=3..8", "Parameter grid, e.g. \"minCount=3..8\" or \"count=2,3,4\"")
+		intervalsStr = flag.String("intervals", "1h,4h,1d", "Comma-separated intervals to test")
+		days         = flag.Int("days", 90, "Number of days to optimise over")
+		symbolsStr   = flag.String("symbols", "", "Comma-separated list of symbols (empty = all USDT symbols)")
+		oos          = flag.Float64("oos", 0.3, "Fraction of the window held out as out-of-sample")
+		rank         = flag.String("rank", "profitFactor", "In-sample metric to rank or choose by (profitFactor, sharpe, totalReturn, winRate, maxDrawdown)")
+		top          = flag.Int("top", 20, "Number of rows to print")
+		workers      = flag.Int("workers", runtime.NumCPU(), "Number of backtests to run in parallel")
+		walkForward  = flag.Bool("walkforward", false, "Run walk-forward analysis instead of a single in/out-of-sample split")
+		isDays       = flag.Int("is-days", 60, "Walk-forward in-sample window length in days")
+		oosDays      = flag.Int("oos-days", 15, "Walk-forward out-of-sample window length (and step) in days")
+		save         = flag.Bool("save", true, "Save results to file")
+		output       = flag.String("output", "", "Output directory for results (defaults to backtest.resultsPath)")
+		useCache     = flag.Bool("cache", true, "Serve candles from the local store under backtest.dataPath")
+	)
+	flag.Parse()
+
+	cfg := config.Load(*configFile)
+
+	// Ctrl+C or SIGTERM cancels outstanding requests
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	registry := strategies.DefaultRegistry()
+	if _, err := registry.Build(*strategyName, nil); err != nil {
+		log.Fatalf("Invalid strategy: %v", err)
+	}
+	factory := func(params backtester.ParamSet) (types.PatternMatcher, error) {
+		values := make(map[string]interface{}, len(params))
+		for k, v := range params {
+			values[k] = v
+		}
+		return registry.Build(*strategyName, values)
+	}
+
+	grid, err := backtester.ParseGrid(*paramsStr)
+	if err != nil {
+		log.Fatalf("Invalid parameter grid: %v", err)
+	}
+	paramSets := backtester.ExpandGrid(grid)
+
+	if *days <= 0 {
+		log.Fatalf("days must be positive, got %d", *days)
+	}
+	end := time.Now().UTC()
+	start := end.AddDate(0, 0, -*days)
+
+	intervals := splitList(*intervalsStr)
+	for _, interval := range intervals {
+		if _, err := types.ParseInterval(interval); err != nil {
+			log.Fatalf("Invalid interval: %v", err)
+		}
+	}
+
+	client := bybit.NewClient(&cfg.Bybit)
+
+	var provider types.MarketDataProvider = client
+	if *useCache {
+		provider = candlestore.NewProvider(candlestore.NewStore(cfg.Backtest.DataPath), client)
+	}
+
+	symbols := splitList(strings.ToUpper(*symbolsStr))
+	if len(symbols) == 0 {
+		symbols, err = provider.GetSymbols(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get symbols: %v", err)
+		}
+	}
+
+	log.Printf("Optimising %s: %d parameter sets x %d intervals on %d symbols, %d workers",
+		*strategyName, len(paramSets), len(intervals), len(symbols), *workers)
+
+	optimizer := backtester.NewOptimizer(provider, factory, cfg.Backtest.Execution)
+	optimizer.Workers = *workers
+
+	resultsPath := cfg.Backtest.ResultsPath
+	if *output != "" {
+		resultsPath = *output
+	}
+
+	if *walkForward {
+		var wfResults []*backtester.WalkForwardResult
+		for _, interval := range intervals {
+			result, err := optimizer.WalkForward(ctx, symbols, interval, paramSets, start, end,
+				time.Duration(*isDays)*24*time.Hour, time.Duration(*oosDays)*24*time.Hour, *rank)
+			if err != nil {
+				log.Fatalf("Walk-forward failed on %s: %v", interval, err)
+			}
+			printWalkForward(result)
+			wfResults = append(wfResults, result)
+		}
+
+		if *save {
+			path, err := saveResults(wfResults, resultsPath, "walkforward_"+*strategyName)
+			if err != nil {
+				log.Fatalf("Failed to save results: %v", err)
+			}
+			log.Printf("Saved results to %s", path)
+		}
+		return
+	}
+
+	results, err := optimizer.Run(ctx, symbols, intervals, paramSets, start, end, *oos)
+	if err != nil {
+		log.Fatalf("Optimisation failed: %v", err)
+	}
+
+	if err := backtester.RankResults(results, *rank); err != nil {
+		log.Fatalf("Failed to rank results: %v", err)
+	}
+
+	printTable(results, *top)
+
+	if *save {
+		path, err := saveResults(results, resultsPath, "optimize_"+*strategyName)
+		if err != nil {
+			log.Fatalf("Failed to save results: %v", err)
+		}
+		log.Printf("Saved results to %s", path)
+	}
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func printTable(results []backtester.OptimizeResult, top int) {
+	fmt.Printf("\n%-4s %-20s %-4s | %6s %6s %7s %8s %7s %6s | %6s %6s %7s %8s %7s %6s\n",
+		"#", "params", "tf",
+		"trades", "win%", "pf", "return%", "maxDD%", "sharpe",
+		"trades", "win%", "pf", "return%", "maxDD%", "sharpe")
+	fmt.Printf("%-31s | %-45s | %s\n", "", "in-sample", "out-of-sample")
+
+	for i, r := range results {
+		if i >= top {
+			break
+		}
+		is, oos := r.InSample, r.OutOfSample
+		fmt.Printf("%-4d %-20s %-4s | %6d %6.1f %7.2f %+8.2f %7.2f %6.2f | %6d %6.1f %7.2f %+8.2f %7.2f %6.2f\n",
+			i+1, r.Params, r.Interval,
+			is.Trades, is.WinRate, is.ProfitFactor, is.TotalReturn, is.MaxDrawdown, is.Sharpe,
+			oos.Trades, oos.WinRate, oos.ProfitFactor, oos.TotalReturn, oos.MaxDrawdown, oos.Sharpe)
+	}
+}
+
+func printWalkForward(result *backtester.WalkForwardResult) {
+	fmt.Printf("\n=== Walk-forward %s (best %s) ===\n", result.Interval, result.Metric)
+	fmt.Printf("%-12s %-12s %-20s | %6s %7s %8s | %6s %7s %8s\n",
+		"oos start", "oos end", "params", "trades", "pf", "return%", "trades", "pf", "return%")
+	for _, w := range result.Windows {
+		fmt.Printf("%-12s %-12s %-20s | %6d %7.2f %+8.2f | %6d %7.2f %+8.2f\n",
+			w.OutOfSampleStart.Format("2006-01-02"), w.OutOfSampleEnd.Format("2006-01-02"), w.Params,
+			w.InSample.Trades, w.InSample.ProfitFactor, w.InSample.TotalReturn,
+			w.OutOfSample.Trades, w.OutOfSample.ProfitFactor, w.OutOfSample.TotalReturn)
+	}
+
+	s := result.Stats
+	fmt.Printf("Stitched out-of-sample: %d trades, win rate %.1f%%, profit factor %.2f, return %+.2f%%, max drawdown %.2f%%, Sharpe %.2f\n",
+		s.Trades, s.WinRate, s.ProfitFactor, s.TotalReturn, s.MaxDrawdown, s.Sharpe)
+}
+
+func saveResults(results interface{}, dir, prefix string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create results directory: %w", err)
+	}
+
+	fileName := fmt.Sprintf("%s_%s.json", prefix, time.Now().Format("20060102_150405"))
+	path := filepath.Join(dir, fileName)
+
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal results: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write results file: %w", err)
+	}
+
+	return path, nil
+}
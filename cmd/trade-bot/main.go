@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/letieu/trade-bot/internal/bot"
@@ -32,8 +36,18 @@ func main() {
 		cfg.Bot.RunOnce = true // -time implies -once for logic clarity
 	}
 
+	// Stop scanning on SIGINT/SIGTERM; a second signal exits immediately
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		log.Println("Shutdown requested, finishing in-flight scans...")
+		stop()
+	}()
+
 	tradingBot := bot.NewBot(cfg)
-	if err := tradingBot.Start(); err != nil {
+	if err := tradingBot.Start(ctx); err != nil {
 		log.Fatalf("Failed to start trading bot: %v", err)
 	}
+	log.Println("Trading bot stopped")
 }
@@ -0,0 +1,273 @@
+package backtester
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/indicators"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+type Backtester struct {
+	provider   types.MarketDataProvider
+	strategies []types.PatternMatcher
+
+	// Horizons are the forward bar counts used to measure signal outcomes
+	Horizons []int
+
+	// Execution enables trade simulation when set
+	Execution *config.ExecutionConfig
+
+	// DataEnd, when set, caps the forward bars trades and outcomes may use, so
+	// an in-sample run cannot exit or score on the out-of-sample bars after it
+	DataEnd time.Time
+}
+
+// MissingSignal records a symbol that could not be evaluated during a backtest
+type MissingSignal struct {
+	Time   time.Time `json:"time"`
+	Symbol string    `json:"symbol"`
+	Reason string    `json:"reason"`
+}
+
+// Result holds the outcome of backtesting a single strategy on a single interval.
+// The JSON shape matches the result files already stored under results/.
+type Result struct {
+	Strategy string `json:"-"`
+	Interval string `json:"-"`
+
+	TotalSignals    int             `json:"totalSignals"`
+	SignalsBySymbol map[string]int  `json:"signalsBySymbol"`
+	SignalsByTime   []types.Signal  `json:"signalsByTime"`
+	MissingSignals  []MissingSignal `json:"missingSignals"`
+	StartTime       time.Time       `json:"startTime"`
+	EndTime         time.Time       `json:"endTime"`
+	Duration        time.Duration   `json:"duration"`
+
+	// Forward-return outcome of every signal, in the same order as SignalsByTime
+	Outcomes     []Outcome      `json:"outcomes,omitempty"`
+	OutcomeStats []OutcomeStats `json:"outcomeStats,omitempty"`
+
+	// Simulated trades, only populated when execution is enabled
+	Trades      []Trade       `json:"trades,omitempty"`
+	EquityCurve []EquityPoint `json:"equityCurve,omitempty"`
+	TradeStats  *TradeStats   `json:"tradeStats,omitempty"`
+}
+
+func NewBacktester(provider types.MarketDataProvider, strategies []types.PatternMatcher) *Backtester {
+	return &Backtester{
+		provider:   provider,
+		strategies: strategies,
+		Horizons:   DefaultHorizons,
+	}
+}
+
+// Run walks the closed candles of every symbol between start and end and
+// evaluates each strategy at every bar. One Result is returned per strategy.
+// Cancelling ctx stops the run between symbols.
+func (b *Backtester) Run(ctx context.Context, symbols []string, interval string, start, end time.Time) ([]*Result, error) {
+	duration, err := types.ParseInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("end time %s must be after start time %s", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+	if b.Execution != nil {
+		if err := ValidateExecution(b.Execution); err != nil {
+			return nil, fmt.Errorf("invalid execution config: %w", err)
+		}
+	}
+
+	maxRequired := 0
+	for _, strategy := range b.strategies {
+		if req := strategy.GetRequiredCandles(); req > maxRequired {
+			maxRequired = req
+		}
+	}
+
+	results := make([]*Result, len(b.strategies))
+	for i, strategy := range b.strategies {
+		results[i] = &Result{
+			Strategy:        strategy.GetName(),
+			Interval:        interval,
+			SignalsBySymbol: make(map[string]int),
+			SignalsByTime:   []types.Signal{},
+			MissingSignals:  []MissingSignal{},
+			StartTime:       start,
+			EndTime:         end,
+			Duration:        end.Sub(start),
+		}
+	}
+
+	maxHorizon := 0
+	for _, h := range b.Horizons {
+		if h > maxHorizon {
+			maxHorizon = h
+		}
+	}
+	if b.Execution != nil && b.Execution.MaxBars+1 > maxHorizon {
+		maxHorizon = b.Execution.MaxBars + 1
+	}
+
+	// Fetch past the window so signals near the end still have forward bars
+	fetchEnd := end.Add(time.Duration(maxHorizon) * duration)
+	if now := time.Now(); fetchEnd.After(now) {
+		fetchEnd = now
+	}
+	if !b.DataEnd.IsZero() && fetchEnd.After(b.DataEnd) {
+		fetchEnd = b.DataEnd
+	}
+
+	// Bars inside the window plus enough history to evaluate the first one
+	limit := int(fetchEnd.Sub(start)/duration) + maxRequired
+
+	for _, symbol := range symbols {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		candles, err := b.provider.GetCandles(ctx, symbol, interval, limit, fetchEnd.UnixMilli())
+		if err != nil {
+			log.Printf("Failed to get candles for %s: %v", symbol, err)
+			for _, result := range results {
+				result.addMissing(symbol, err.Error())
+			}
+			continue
+		}
+
+		candles = closedCandles(candles, duration, fetchEnd)
+
+		var atr []float64
+		if b.Execution != nil && b.Execution.StopType == StopATR {
+			atr = indicators.ATRSeries(candles, b.Execution.ATRPeriod)
+		}
+
+		for i, strategy := range b.strategies {
+			result := results[i]
+			required := strategy.GetRequiredCandles()
+			if len(candles) < required || !closedBy(candles[required-1], duration, end) {
+				result.addMissing(symbol, "insufficient candles")
+				continue
+			}
+
+			// Only one simulated position per symbol and strategy at a time
+			openUntil := -1
+
+			for idx := required - 1; idx < len(candles); idx++ {
+				if candles[idx].Timestamp < start.UnixMilli() {
+					continue
+				}
+				if !closedBy(candles[idx], duration, end) {
+					break
+				}
+
+				window := candles[idx+1-required : idx+1]
+				match, err := strategy.Match(window)
+				if err != nil {
+					log.Printf("Error matching pattern %s for %s: %v", strategy.GetName(), symbol, err)
+					continue
+				}
+				if match == nil {
+					continue
+				}
+
+				// Signals are stamped with the close time of the candle that completed them
+				closeTime := time.UnixMilli(candles[idx].Timestamp).Add(duration).UTC()
+				signal := types.NewSignal(symbol, interval, strategy.GetName(), match, closeTime)
+				result.TotalSignals++
+				result.SignalsBySymbol[symbol]++
+				result.SignalsByTime = append(result.SignalsByTime, signal)
+				result.Outcomes = append(result.Outcomes, computeOutcome(signal, candles[idx], candles[idx+1:], b.Horizons))
+
+				if b.Execution != nil && idx >= openUntil {
+					if trade, exitIdx, ok := simulateTrade(b.Execution, signal, candles, idx, atr, duration); ok {
+						result.Trades = append(result.Trades, trade)
+						openUntil = exitIdx
+					}
+				}
+			}
+		}
+	}
+
+	for _, result := range results {
+		sort.Stable(byTime{result})
+		result.OutcomeStats = AggregateOutcomes(result.Outcomes, b.Horizons)
+
+		if b.Execution != nil {
+			result.EquityCurve = buildEquityCurve(result.Trades, b.Execution.InitialCapital, b.Execution.PositionSize)
+			stats := computeTradeStats(result.Trades, result.EquityCurve, b.Execution.InitialCapital)
+			result.TradeStats = &stats
+		}
+	}
+
+	return results, nil
+}
+
+func (r *Result) addMissing(symbol, reason string) {
+	r.MissingSignals = append(r.MissingSignals, MissingSignal{
+		Time:   time.Now(),
+		Symbol: symbol,
+		Reason: reason,
+	})
+}
+
+// byTime sorts signals chronologically, keeping outcomes aligned with them
+type byTime struct{ r *Result }
+
+func (s byTime) Len() int { return len(s.r.SignalsByTime) }
+func (s byTime) Less(i, j int) bool {
+	return s.r.SignalsByTime[i].Timestamp.Before(s.r.SignalsByTime[j].Timestamp)
+}
+func (s byTime) Swap(i, j int) {
+	s.r.SignalsByTime[i], s.r.SignalsByTime[j] = s.r.SignalsByTime[j], s.r.SignalsByTime[i]
+	s.r.Outcomes[i], s.r.Outcomes[j] = s.r.Outcomes[j], s.r.Outcomes[i]
+}
+
+func closedBy(candle types.Candle, duration time.Duration, end time.Time) bool {
+	return candle.Timestamp+duration.Milliseconds() <= end.UnixMilli()
+}
+
+// closedCandles drops candles that had not closed by end
+func closedCandles(candles []types.Candle, duration time.Duration, end time.Time) []types.Candle {
+	cutoff := end.UnixMilli()
+	if now := time.Now().UnixMilli(); now < cutoff {
+		cutoff = now
+	}
+
+	closed := candles[:0:0]
+	for _, candle := range candles {
+		if closedBy(candle, duration, time.UnixMilli(cutoff)) {
+			closed = append(closed, candle)
+		}
+	}
+	return closed
+}
+
+// SaveResult writes the result as JSON into dir and returns the file path
+func SaveResult(result *Result, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create results directory: %w", err)
+	}
+
+	fileName := fmt.Sprintf("backtest_%s_%s_%s.json", result.Strategy, result.Interval, time.Now().Format("20060102_150405"))
+	path := filepath.Join(dir, fileName)
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write result file: %w", err)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,175 @@
+package backtester
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/strategies"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+type mockProvider struct {
+	candles map[string][]types.Candle
+}
+
+func (m *mockProvider) GetSymbols(ctx context.Context) ([]string, error) {
+	var symbols []string
+	for symbol := range m.candles {
+		symbols = append(symbols, symbol)
+	}
+	return symbols, nil
+}
+
+func (m *mockProvider) GetCandles(ctx context.Context, symbol, interval string, limit int, endTime int64) ([]types.Candle, error) {
+	return m.candles[symbol], nil
+}
+
+// makeCandles builds hourly candles starting at start from (open, close) pairs
+func makeCandles(start time.Time, prices ...[2]float64) []types.Candle {
+	candles := make([]types.Candle, len(prices))
+	for i, p := range prices {
+		candles[i] = types.Candle{
+			Timestamp: start.Add(time.Duration(i) * time.Hour).UnixMilli(),
+			Open:      p[0],
+			Close:     p[1],
+			High:      p[0] + 1,
+			Low:       p[1] - 1,
+			Volume:    1000,
+		}
+	}
+	return candles
+}
+
+func TestBacktester_Run(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(8 * time.Hour)
+
+	provider := &mockProvider{candles: map[string][]types.Candle{
+		// Warmup, then Red, Red, Red, Green, Red, Red, Red, Green -> two reversals
+		"BTCUSDT": makeCandles(start.Add(-time.Hour),
+			[2]float64{100, 100}, [2]float64{100, 90}, [2]float64{90, 80}, [2]float64{80, 70}, [2]float64{70, 75},
+			[2]float64{75, 70}, [2]float64{70, 65}, [2]float64{65, 60}, [2]float64{60, 62},
+		),
+		"ETHUSDT": makeCandles(start, [2]float64{100, 90}),
+	}}
+
+	bt := NewBacktester(provider, []types.PatternMatcher{strategies.NewThreeCandleReversal()})
+	results, err := bt.Run(context.Background(), []string{"BTCUSDT", "ETHUSDT"}, "1h", start, end)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+
+	result := results[0]
+	if result.TotalSignals != 2 {
+		t.Errorf("TotalSignals = %d, want 2", result.TotalSignals)
+	}
+	if result.SignalsBySymbol["BTCUSDT"] != 2 {
+		t.Errorf("SignalsBySymbol[BTCUSDT] = %d, want 2", result.SignalsBySymbol["BTCUSDT"])
+	}
+	if len(result.MissingSignals) != 1 || result.MissingSignals[0].Symbol != "ETHUSDT" {
+		t.Errorf("expected ETHUSDT to be missing, got %+v", result.MissingSignals)
+	}
+
+	first := result.SignalsByTime[0]
+	if want := start.Add(4 * time.Hour); !first.Timestamp.Equal(want) {
+		t.Errorf("first signal timestamp = %v, want %v", first.Timestamp, want)
+	}
+	if first.Trend != "bullish" {
+		t.Errorf("first signal trend = %s, want bullish", first.Trend)
+	}
+	if result.Duration != 8*time.Hour {
+		t.Errorf("Duration = %v, want %v", result.Duration, 8*time.Hour)
+	}
+}
+
+func TestBacktester_Run_DataEnd(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	split := start.Add(4 * time.Hour)
+
+	// Two greens signal on the 01:00 close, the target is only reached after split
+	provider := &mockProvider{candles: map[string][]types.Candle{
+		"BTCUSDT": makeCandles(start.Add(-time.Hour),
+			[2]float64{101, 100}, [2]float64{100, 101}, [2]float64{101, 102},
+			[2]float64{102, 102}, [2]float64{102, 102},
+			[2]float64{104, 110},
+		),
+	}}
+
+	run := func(dataEnd time.Time) *Result {
+		bt := NewBacktester(provider, []types.PatternMatcher{strategies.NewConsecutiveCandles(2)})
+		bt.Execution = &config.ExecutionConfig{
+			Entry:          EntrySignalClose,
+			StopType:       StopPercent,
+			StopLoss:       50,
+			TakeProfit:     1,
+			InitialCapital: 1000,
+			PositionSize:   1,
+		}
+		bt.DataEnd = dataEnd
+		results, err := bt.Run(context.Background(), []string{"BTCUSDT"}, "1h", start, split)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if len(results[0].Trades) == 0 {
+			t.Fatal("expected a trade")
+		}
+		return results[0]
+	}
+
+	if trade := run(time.Time{}).Trades[0]; trade.ExitReason != ExitTakeProfit {
+		t.Errorf("uncapped exit = %s, want %s after split", trade.ExitReason, ExitTakeProfit)
+	}
+
+	capped := run(split)
+	if trade := capped.Trades[0]; trade.ExitReason != ExitEndOfData || trade.ExitTime.After(split) {
+		t.Errorf("capped exit = %s at %v, want %s by %v", trade.ExitReason, trade.ExitTime, ExitEndOfData, split)
+	}
+	if _, ok := capped.Outcomes[0].Returns[3]; ok {
+		t.Errorf("capped outcome has a 3 bar return, which needs bars after split")
+	}
+}
+
+func TestSaveResult_JSONShape(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	result := &Result{
+		Strategy:        "ĐẢO CHIỀU",
+		Interval:        "1h",
+		SignalsBySymbol: map[string]int{},
+		SignalsByTime:   []types.Signal{},
+		MissingSignals:  []MissingSignal{},
+		StartTime:       start,
+		EndTime:         start.Add(time.Hour),
+		Duration:        time.Hour,
+	}
+
+	path, err := SaveResult(result, t.TempDir())
+	if err != nil {
+		t.Fatalf("SaveResult() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read result: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+
+	for _, key := range []string{"totalSignals", "signalsBySymbol", "signalsByTime", "missingSignals", "startTime", "endTime", "duration"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("missing key %q in result JSON", key)
+		}
+	}
+	if len(decoded) != 7 {
+		t.Errorf("expected 7 keys in result JSON, got %d", len(decoded))
+	}
+}
@@ -0,0 +1,392 @@
+package backtester
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// ParamSet holds one combination of strategy parameters
+type ParamSet map[string]float64
+
+func (p ParamSet) String() string {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%s", k, strconv.FormatFloat(p[k], 'f', -1, 64))
+	}
+	return strings.Join(parts, ",")
+}
+
+// StrategyFactory builds a strategy from a parameter set
+type StrategyFactory func(params ParamSet) (types.PatternMatcher, error)
+
+// ParseGrid parses a parameter grid such as "minCount=3..8;threshold=1.5,2,3".
+// Ranges accept an optional step: "1..2:0.25".
+func ParseGrid(spec string) (map[string][]float64, error) {
+	grid := make(map[string][]float64)
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, values, ok := strings.Cut(part, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid parameter %q, expected name=values", part)
+		}
+		name = strings.TrimSpace(name)
+
+		var parsed []float64
+		if from, to, isRange := strings.Cut(values, ".."); isRange {
+			step := 1.0
+			if t, s, hasStep := strings.Cut(to, ":"); hasStep {
+				to = t
+				v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+				if err != nil || v <= 0 {
+					return nil, fmt.Errorf("invalid step %q for %s", s, name)
+				}
+				step = v
+			}
+			lo, err := strconv.ParseFloat(strings.TrimSpace(from), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid range start %q for %s", from, name)
+			}
+			hi, err := strconv.ParseFloat(strings.TrimSpace(to), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid range end %q for %s", to, name)
+			}
+			if hi < lo {
+				return nil, fmt.Errorf("range end %v is below start %v for %s", hi, lo, name)
+			}
+			// Count steps instead of accumulating to avoid float drift
+			for i := 0; lo+float64(i)*step <= hi+1e-9; i++ {
+				parsed = append(parsed, lo+float64(i)*step)
+			}
+		} else {
+			for _, v := range strings.Split(values, ",") {
+				f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value %q for %s", v, name)
+				}
+				parsed = append(parsed, f)
+			}
+		}
+
+		grid[name] = parsed
+	}
+
+	if len(grid) == 0 {
+		return nil, fmt.Errorf("empty parameter grid")
+	}
+	return grid, nil
+}
+
+// ExpandGrid returns the cartesian product of a parameter grid in a stable order
+func ExpandGrid(grid map[string][]float64) []ParamSet {
+	names := make([]string, 0, len(grid))
+	for name := range grid {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sets := []ParamSet{{}}
+	for _, name := range names {
+		var next []ParamSet
+		for _, set := range sets {
+			for _, v := range grid[name] {
+				p := make(ParamSet, len(set)+1)
+				for k, existing := range set {
+					p[k] = existing
+				}
+				p[name] = v
+				next = append(next, p)
+			}
+		}
+		sets = next
+	}
+	return sets
+}
+
+// OptimizeResult holds the in-sample and out-of-sample metrics of one grid point
+type OptimizeResult struct {
+	Params      ParamSet   `json:"params"`
+	Interval    string     `json:"interval"`
+	InSample    TradeStats `json:"inSample"`
+	OutOfSample TradeStats `json:"outOfSample"`
+}
+
+// Optimizer runs a backtest for every parameter combination and interval
+type Optimizer struct {
+	provider  types.MarketDataProvider
+	factory   StrategyFactory
+	execution config.ExecutionConfig
+
+	// Workers is the number of backtests run in parallel
+	Workers int
+}
+
+func NewOptimizer(provider types.MarketDataProvider, factory StrategyFactory, execution config.ExecutionConfig) *Optimizer {
+	return &Optimizer{
+		provider:  provider,
+		factory:   factory,
+		execution: execution,
+		Workers:   runtime.NumCPU(),
+	}
+}
+
+// Run evaluates every parameter set on every interval. The last oosFraction of
+// the window is held out and scored separately so the ranking can be checked
+// against data the parameters were not chosen on.
+func (o *Optimizer) Run(ctx context.Context, symbols, intervals []string, grid []ParamSet, start, end time.Time, oosFraction float64) ([]OptimizeResult, error) {
+	if oosFraction < 0 || oosFraction >= 1 {
+		return nil, fmt.Errorf("out-of-sample fraction must be in [0, 1), got %v", oosFraction)
+	}
+	if err := ValidateExecution(&o.execution); err != nil {
+		return nil, fmt.Errorf("invalid execution config: %w", err)
+	}
+
+	split := end.Add(-time.Duration(float64(end.Sub(start)) * oosFraction))
+
+	maxRequired, err := o.maxRequired(grid)
+	if err != nil {
+		return nil, err
+	}
+
+	type job struct {
+		params   ParamSet
+		interval string
+		provider types.MarketDataProvider
+	}
+
+	var jobs []job
+	for _, interval := range intervals {
+		provider, err := o.preload(ctx, symbols, interval, start, end, maxRequired)
+		if err != nil {
+			return nil, err
+		}
+		for _, params := range grid {
+			jobs = append(jobs, job{params: params, interval: interval, provider: provider})
+		}
+	}
+
+	results := make([]OptimizeResult, len(jobs))
+	err = o.parallel(len(jobs), func(i int) error {
+		var err error
+		results[i], err = o.evaluate(ctx, jobs[i].provider, jobs[i].params, jobs[i].interval, start, split, end)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (o *Optimizer) evaluate(ctx context.Context, provider types.MarketDataProvider, params ParamSet, interval string, start, split, end time.Time) (OptimizeResult, error) {
+	result := OptimizeResult{Params: params, Interval: interval}
+
+	symbols, err := provider.GetSymbols(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	// The in-sample trades must not see the bars held out after split
+	inSample, err := o.runWindow(ctx, provider, params, interval, symbols, start, split, split)
+	if err != nil {
+		return result, err
+	}
+	result.InSample = *inSample.TradeStats
+
+	if split.Before(end) {
+		outOfSample, err := o.runWindow(ctx, provider, params, interval, symbols, split, end, time.Time{})
+		if err != nil {
+			return result, err
+		}
+		result.OutOfSample = *outOfSample.TradeStats
+	}
+
+	return result, nil
+}
+
+// maxRequired builds every strategy up front so bad parameters fail before
+// any work starts, and returns the largest candle requirement
+func (o *Optimizer) maxRequired(grid []ParamSet) (int, error) {
+	maxRequired := 0
+	for _, params := range grid {
+		strategy, err := o.factory(params)
+		if err != nil {
+			return 0, fmt.Errorf("invalid parameters %s: %w", params, err)
+		}
+		if req := strategy.GetRequiredCandles(); req > maxRequired {
+			maxRequired = req
+		}
+	}
+	return maxRequired, nil
+}
+
+// parallel calls fn for 0..n-1 on the configured number of workers and
+// returns the first error in index order
+func (o *Optimizer) parallel(n int, fn func(i int) error) error {
+	workers := o.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	errs := make([]error, n)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runWindow backtests params on signals in [start, end). Trades and outcomes
+// may run past end up to dataEnd, or without a cap when dataEnd is zero.
+func (o *Optimizer) runWindow(ctx context.Context, provider types.MarketDataProvider, params ParamSet, interval string, symbols []string, start, end, dataEnd time.Time) (*Result, error) {
+	strategy, err := o.factory(params)
+	if err != nil {
+		return nil, err
+	}
+
+	execution := o.execution
+	bt := NewBacktester(provider, []types.PatternMatcher{strategy})
+	bt.Execution = &execution
+	bt.DataEnd = dataEnd
+
+	results, err := bt.Run(ctx, symbols, interval, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return results[0], nil
+}
+
+// preload fetches the whole window once per interval so every grid point
+// reuses the same candles instead of hitting the provider again
+func (o *Optimizer) preload(ctx context.Context, symbols []string, interval string, start, end time.Time, maxRequired int) (types.MarketDataProvider, error) {
+	duration, err := types.ParseInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	forward := DefaultHorizons[len(DefaultHorizons)-1]
+	if o.execution.MaxBars+1 > forward {
+		forward = o.execution.MaxBars + 1
+	}
+	fetchEnd := end.Add(time.Duration(forward) * duration)
+	if now := time.Now(); fetchEnd.After(now) {
+		fetchEnd = now
+	}
+	limit := int(fetchEnd.Sub(start)/duration) + maxRequired
+
+	log.Printf("[%s] Loading candles for %d symbols", interval, len(symbols))
+
+	store := &staticProvider{candles: make(map[string][]types.Candle)}
+	for _, symbol := range symbols {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		candles, err := o.provider.GetCandles(ctx, symbol, interval, limit, fetchEnd.UnixMilli())
+		if err != nil {
+			log.Printf("Failed to get candles for %s: %v", symbol, err)
+			continue
+		}
+		store.symbols = append(store.symbols, symbol)
+		store.candles[symbol] = candles
+	}
+	return store, nil
+}
+
+// staticProvider serves candles that were loaded ahead of time
+type staticProvider struct {
+	symbols []string
+	candles map[string][]types.Candle
+}
+
+func (p *staticProvider) GetSymbols(ctx context.Context) ([]string, error) {
+	return p.symbols, nil
+}
+
+func (p *staticProvider) GetCandles(ctx context.Context, symbol, interval string, limit int, endTime int64) ([]types.Candle, error) {
+	candles, ok := p.candles[symbol]
+	if !ok {
+		return nil, fmt.Errorf("no candles loaded for %s", symbol)
+	}
+	return candles, nil
+}
+
+// RankResults sorts results by the given in-sample metric, best first
+func RankResults(results []OptimizeResult, metric string) error {
+	value, err := metricFunc(metric)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return better(value, results[i].InSample, results[j].InSample)
+	})
+	return nil
+}
+
+// better reports whether a beats b on the metric. Ties, such as two
+// all-winning sets with an infinite profit factor, go to the higher return.
+func better(value func(TradeStats) float64, a, b TradeStats) bool {
+	va, vb := value(a), value(b)
+	if va != vb {
+		return va > vb
+	}
+	return a.TotalReturn > b.TotalReturn
+}
+
+// metricFunc returns a scorer where higher is better for the named metric
+func metricFunc(metric string) (func(TradeStats) float64, error) {
+	switch metric {
+	case "profitFactor":
+		return func(s TradeStats) float64 { return s.ProfitFactor }, nil
+	case "sharpe":
+		return func(s TradeStats) float64 { return s.Sharpe }, nil
+	case "totalReturn":
+		return func(s TradeStats) float64 { return s.TotalReturn }, nil
+	case "winRate":
+		return func(s TradeStats) float64 { return s.WinRate }, nil
+	case "maxDrawdown":
+		return func(s TradeStats) float64 { return -s.MaxDrawdown }, nil
+	default:
+		return nil, fmt.Errorf("unknown metric %q (use profitFactor, sharpe, totalReturn, winRate or maxDrawdown)", metric)
+	}
+}
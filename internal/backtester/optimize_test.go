@@ -0,0 +1,139 @@
+package backtester
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/strategies"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+func TestParseGrid(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    map[string][]float64
+		wantErr bool
+	}{
+		{spec: "minCount=3..5", want: map[string][]float64{"minCount": {3, 4, 5}}},
+		{spec: "x=1..2:0.5; y=7,9", want: map[string][]float64{"x": {1, 1.5, 2}, "y": {7, 9}}},
+		{spec: "minCount=5..3", wantErr: true},
+		{spec: "minCount", wantErr: true},
+		{spec: "minCount=a", wantErr: true},
+		{spec: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseGrid(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGrid(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseGrid(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandGrid(t *testing.T) {
+	sets := ExpandGrid(map[string][]float64{"a": {1, 2}, "b": {3, 4, 5}})
+	if len(sets) != 6 {
+		t.Fatalf("expected 6 parameter sets, got %d", len(sets))
+	}
+	if sets[0].String() != "a=1,b=3" || sets[5].String() != "a=2,b=5" {
+		t.Errorf("unexpected order: first %s, last %s", sets[0], sets[5])
+	}
+}
+
+type countingProvider struct {
+	mockProvider
+	calls int
+}
+
+func (p *countingProvider) GetCandles(ctx context.Context, symbol, interval string, limit int, endTime int64) ([]types.Candle, error) {
+	p.calls++
+	return p.mockProvider.GetCandles(ctx, symbol, interval, limit, endTime)
+}
+
+func TestOptimizer_Run(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(40 * time.Hour)
+
+	var prices [][2]float64
+	for i := 0; i < 50; i++ {
+		// Alternating runs of three reds and three greens
+		if (i/3)%2 == 0 {
+			prices = append(prices, [2]float64{100, 99})
+		} else {
+			prices = append(prices, [2]float64{99, 100})
+		}
+	}
+
+	provider := &countingProvider{mockProvider: mockProvider{candles: map[string][]types.Candle{
+		"BTCUSDT": makeCandles(start.Add(-5*time.Hour), prices...),
+	}}}
+
+	factory := func(params ParamSet) (types.PatternMatcher, error) {
+		return strategies.NewConsecutiveCandles(int(params["minCount"])), nil
+	}
+
+	execution := config.ExecutionConfig{
+		Entry:          EntrySignalClose,
+		StopType:       StopPercent,
+		MaxBars:        1,
+		InitialCapital: 1000,
+		PositionSize:   1,
+	}
+
+	optimizer := NewOptimizer(provider, factory, execution)
+	grid := ExpandGrid(map[string][]float64{"minCount": {2, 3, 4}})
+
+	results, err := optimizer.Run(context.Background(), []string{"BTCUSDT"}, []string{"1h"}, grid, start, end, 0.25)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if provider.calls != 1 {
+		t.Errorf("expected candles to be fetched once, got %d calls", provider.calls)
+	}
+
+	// Runs never reach four candles, so minCount=4 should never trade
+	for _, r := range results {
+		if r.Params["minCount"] == 4 && (r.InSample.Trades != 0 || r.OutOfSample.Trades != 0) {
+			t.Errorf("minCount=4 should not trade, got %+v", r)
+		}
+		if r.Params["minCount"] == 2 && (r.InSample.Trades == 0 || r.OutOfSample.Trades == 0) {
+			t.Errorf("minCount=2 should trade in both samples, got %+v", r)
+		}
+	}
+
+	if err := RankResults(results, "unknown"); err == nil {
+		t.Error("expected error for unknown metric")
+	}
+}
+
+func TestRankResults_InfiniteProfitFactor(t *testing.T) {
+	results := []OptimizeResult{
+		{Params: ParamSet{"minCount": 1}, InSample: TradeStats{ProfitFactor: 3, TotalReturn: 40}},
+		{Params: ParamSet{"minCount": 2}, InSample: TradeStats{ProfitFactor: math.Inf(1), TotalReturn: 5}},
+		{Params: ParamSet{"minCount": 3}, InSample: TradeStats{ProfitFactor: math.Inf(1), TotalReturn: 12}},
+		{Params: ParamSet{"minCount": 4}},
+	}
+
+	if err := RankResults(results, "profitFactor"); err != nil {
+		t.Fatalf("RankResults() error = %v", err)
+	}
+
+	want := []float64{3, 2, 1, 4}
+	for i, r := range results {
+		if r.Params["minCount"] != want[i] {
+			t.Errorf("rank %d = minCount %v, want %v", i+1, r.Params["minCount"], want[i])
+		}
+	}
+}
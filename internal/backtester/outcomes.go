@@ -0,0 +1,174 @@
+package backtester
+
+import (
+	"sort"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// DefaultHorizons are the forward bar counts used to measure signal outcomes
+var DefaultHorizons = []int{1, 3, 5, 10}
+
+// Outcome describes what price did after a historical signal.
+// Returns are close-to-close percentages keyed by horizon (in bars); horizons
+// that run past the available data are omitted. Excursions are measured in the
+// signal direction over the longest horizon, so MaxFavorable >= 0 >= MaxAdverse.
+type Outcome struct {
+	Symbol       string          `json:"symbol"`
+	Interval     string          `json:"interval"`
+	Pattern      string          `json:"pattern"`
+	Trend        string          `json:"trend"`
+	Timestamp    time.Time       `json:"timestamp"`
+	EntryPrice   float64         `json:"entryPrice"`
+	Returns      map[int]float64 `json:"returns"`
+	MaxFavorable float64         `json:"maxFavorableExcursion"`
+	MaxAdverse   float64         `json:"maxAdverseExcursion"`
+}
+
+// HorizonStats aggregates direction-adjusted returns for a single horizon
+type HorizonStats struct {
+	Bars      int     `json:"bars"`
+	Samples   int     `json:"samples"`
+	HitRate   float64 `json:"hitRate"`   // % of samples that moved in the signal direction
+	AvgReturn float64 `json:"avgReturn"` // direction-adjusted, in %
+}
+
+// OutcomeStats aggregates outcomes per pattern, interval and trend
+type OutcomeStats struct {
+	Pattern         string         `json:"pattern"`
+	Interval        string         `json:"interval"`
+	Trend           string         `json:"trend"`
+	Count           int            `json:"count"`
+	Horizons        []HorizonStats `json:"horizons"`
+	AvgMaxFavorable float64        `json:"avgMaxFavorableExcursion"`
+	AvgMaxAdverse   float64        `json:"avgMaxAdverseExcursion"`
+}
+
+// computeOutcome measures the forward returns of a signal raised on the close
+// of entry, using the candles that follow it.
+func computeOutcome(signal types.Signal, entry types.Candle, forward []types.Candle, horizons []int) Outcome {
+	outcome := Outcome{
+		Symbol:     signal.Symbol,
+		Interval:   signal.Interval,
+		Pattern:    signal.Pattern,
+		Trend:      signal.Trend,
+		Timestamp:  signal.Timestamp,
+		EntryPrice: entry.Close,
+		Returns:    make(map[int]float64),
+	}
+
+	if entry.Close == 0 {
+		return outcome
+	}
+
+	maxHorizon := 0
+	for _, h := range horizons {
+		if h > maxHorizon {
+			maxHorizon = h
+		}
+		if h > 0 && h <= len(forward) {
+			outcome.Returns[h] = (forward[h-1].Close - entry.Close) / entry.Close * 100
+		}
+	}
+
+	if maxHorizon > len(forward) {
+		maxHorizon = len(forward)
+	}
+
+	bearish := signal.Trend == "bearish"
+	for _, candle := range forward[:maxHorizon] {
+		up := (candle.High - entry.Close) / entry.Close * 100
+		down := (candle.Low - entry.Close) / entry.Close * 100
+
+		favorable, adverse := up, down
+		if bearish {
+			favorable, adverse = -down, -up
+		}
+
+		if favorable > outcome.MaxFavorable {
+			outcome.MaxFavorable = favorable
+		}
+		if adverse < outcome.MaxAdverse {
+			outcome.MaxAdverse = adverse
+		}
+	}
+
+	return outcome
+}
+
+// directionalReturn flips the sign of a return for bearish signals
+func directionalReturn(trend string, ret float64) float64 {
+	if trend == "bearish" {
+		return -ret
+	}
+	return ret
+}
+
+// AggregateOutcomes groups outcomes by pattern, interval and trend
+func AggregateOutcomes(outcomes []Outcome, horizons []int) []OutcomeStats {
+	type groupKey struct {
+		pattern  string
+		interval string
+		trend    string
+	}
+	groups := make(map[groupKey][]Outcome)
+	for _, o := range outcomes {
+		key := groupKey{pattern: o.Pattern, interval: o.Interval, trend: o.Trend}
+		groups[key] = append(groups[key], o)
+	}
+
+	var stats []OutcomeStats
+	for key, group := range groups {
+		s := OutcomeStats{
+			Pattern:  key.pattern,
+			Interval: key.interval,
+			Trend:    key.trend,
+			Count:    len(group),
+		}
+
+		for _, h := range horizons {
+			hs := HorizonStats{Bars: h}
+			hits := 0
+			total := 0.0
+			for _, o := range group {
+				ret, ok := o.Returns[h]
+				if !ok {
+					continue
+				}
+				ret = directionalReturn(o.Trend, ret)
+				hs.Samples++
+				total += ret
+				if ret > 0 {
+					hits++
+				}
+			}
+			if hs.Samples > 0 {
+				hs.HitRate = float64(hits) / float64(hs.Samples) * 100
+				hs.AvgReturn = total / float64(hs.Samples)
+			}
+			s.Horizons = append(s.Horizons, hs)
+		}
+
+		for _, o := range group {
+			s.AvgMaxFavorable += o.MaxFavorable
+			s.AvgMaxAdverse += o.MaxAdverse
+		}
+		s.AvgMaxFavorable /= float64(len(group))
+		s.AvgMaxAdverse /= float64(len(group))
+
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Pattern != stats[j].Pattern {
+			return stats[i].Pattern < stats[j].Pattern
+		}
+		if stats[i].Interval != stats[j].Interval {
+			return stats[i].Interval < stats[j].Interval
+		}
+		return stats[i].Trend < stats[j].Trend
+	})
+
+	return stats
+}
@@ -0,0 +1,87 @@
+package backtester
+
+import (
+	"math"
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+func TestComputeOutcome(t *testing.T) {
+	entry := types.Candle{Open: 95, Close: 100, High: 101, Low: 94}
+	forward := []types.Candle{
+		{Open: 100, Close: 102, High: 104, Low: 99},
+		{Open: 102, Close: 98, High: 103, Low: 95},
+		{Open: 98, Close: 110, High: 112, Low: 97},
+	}
+
+	tests := []struct {
+		name         string
+		trend        string
+		wantFavor    float64
+		wantAdverse  float64
+		wantReturns  map[int]float64
+		wantMissingH int
+	}{
+		{
+			name:         "bullish",
+			trend:        "bullish",
+			wantFavor:    12,
+			wantAdverse:  -5,
+			wantReturns:  map[int]float64{1: 2, 3: 10},
+			wantMissingH: 5,
+		},
+		{
+			name:         "bearish",
+			trend:        "bearish",
+			wantFavor:    5,
+			wantAdverse:  -12,
+			wantReturns:  map[int]float64{1: 2, 3: 10},
+			wantMissingH: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signal := types.Signal{Trend: tt.trend}
+			got := computeOutcome(signal, entry, forward, []int{1, 3, 5})
+
+			for h, want := range tt.wantReturns {
+				if math.Abs(got.Returns[h]-want) > 1e-9 {
+					t.Errorf("Returns[%d] = %v, want %v", h, got.Returns[h], want)
+				}
+			}
+			if _, ok := got.Returns[tt.wantMissingH]; ok {
+				t.Errorf("Returns[%d] should be missing when not enough forward bars", tt.wantMissingH)
+			}
+			if math.Abs(got.MaxFavorable-tt.wantFavor) > 1e-9 {
+				t.Errorf("MaxFavorable = %v, want %v", got.MaxFavorable, tt.wantFavor)
+			}
+			if math.Abs(got.MaxAdverse-tt.wantAdverse) > 1e-9 {
+				t.Errorf("MaxAdverse = %v, want %v", got.MaxAdverse, tt.wantAdverse)
+			}
+		})
+	}
+}
+
+func TestAggregateOutcomes(t *testing.T) {
+	outcomes := []Outcome{
+		{Pattern: "P", Interval: "4h", Trend: "bullish", Returns: map[int]float64{1: 2}},
+		{Pattern: "P", Interval: "4h", Trend: "bullish", Returns: map[int]float64{1: -1}},
+		{Pattern: "P", Interval: "4h", Trend: "bearish", Returns: map[int]float64{1: -3}},
+	}
+
+	stats := AggregateOutcomes(outcomes, []int{1})
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(stats))
+	}
+
+	// Sorted by trend: bearish first
+	bearish, bullish := stats[0], stats[1]
+	if bearish.Horizons[0].HitRate != 100 || bearish.Horizons[0].AvgReturn != 3 {
+		t.Errorf("bearish stats = %+v, want hit 100%% avg +3%%", bearish.Horizons[0])
+	}
+	if bullish.Horizons[0].HitRate != 50 || bullish.Horizons[0].AvgReturn != 0.5 {
+		t.Errorf("bullish stats = %+v, want hit 50%% avg +0.5%%", bullish.Horizons[0])
+	}
+}
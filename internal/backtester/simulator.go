@@ -0,0 +1,320 @@
+package backtester
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+const (
+	EntryNextOpen    = "nextOpen"
+	EntrySignalClose = "signalClose"
+
+	StopPercent = "percent"
+	StopATR     = "atr"
+
+	ExitStopLoss   = "stop_loss"
+	ExitTakeProfit = "take_profit"
+	ExitTime       = "time"
+	ExitEndOfData  = "end_of_data"
+)
+
+// Trade is a single simulated position opened from a signal
+type Trade struct {
+	Symbol     string    `json:"symbol"`
+	Interval   string    `json:"interval"`
+	Pattern    string    `json:"pattern"`
+	Side       string    `json:"side"` // "long" or "short"
+	EntryTime  time.Time `json:"entryTime"`
+	EntryPrice float64   `json:"entryPrice"`
+	ExitTime   time.Time `json:"exitTime"`
+	ExitPrice  float64   `json:"exitPrice"`
+	StopLoss   float64   `json:"stopLoss"`
+	TakeProfit float64   `json:"takeProfit"`
+	ExitReason string    `json:"exitReason"`
+	Bars       int       `json:"bars"`
+	FeesPct    float64   `json:"feesPct"`
+	ReturnPct  float64   `json:"returnPct"` // net of fees and slippage
+	PnL        float64   `json:"pnl"`
+}
+
+// EquityPoint is the account equity after a trade closed
+type EquityPoint struct {
+	Time   time.Time `json:"time"`
+	Equity float64   `json:"equity"`
+}
+
+// TradeStats summarises a trade ledger
+type TradeStats struct {
+	Trades       int     `json:"trades"`
+	Wins         int     `json:"wins"`
+	Losses       int     `json:"losses"`
+	Breakeven    int     `json:"breakeven"`
+	WinRate      float64 `json:"winRate"`      // wins over decided (non-breakeven) trades
+	ProfitFactor float64 `json:"profitFactor"` // +Inf with wins and no losses, written as null in JSON
+	AvgReturn    float64 `json:"avgReturn"`
+	TotalReturn  float64 `json:"totalReturn"`
+	MaxDrawdown  float64 `json:"maxDrawdown"`
+	Sharpe       float64 `json:"sharpe"` // per-trade, not annualised
+	FinalEquity  float64 `json:"finalEquity"`
+}
+
+// tradeStatsJSON mirrors TradeStats with a nullable profit factor, since
+// encoding/json cannot represent +Inf
+type tradeStatsJSON struct {
+	tradeStatsAlias
+	ProfitFactor *float64 `json:"profitFactor"`
+}
+
+type tradeStatsAlias TradeStats
+
+// MarshalJSON writes an infinite profit factor as null
+func (s TradeStats) MarshalJSON() ([]byte, error) {
+	out := tradeStatsJSON{tradeStatsAlias: tradeStatsAlias(s)}
+	if !math.IsInf(s.ProfitFactor, 1) {
+		out.ProfitFactor = &s.ProfitFactor
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON reads a null profit factor back as +Inf when the ledger has
+// wins and no losses
+func (s *TradeStats) UnmarshalJSON(data []byte) error {
+	var in tradeStatsJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*s = TradeStats(in.tradeStatsAlias)
+	switch {
+	case in.ProfitFactor != nil:
+		s.ProfitFactor = *in.ProfitFactor
+	case s.Wins > 0 && s.Losses == 0:
+		s.ProfitFactor = math.Inf(1)
+	}
+	return nil
+}
+
+// ValidateExecution checks an execution config before it is used for simulation
+func ValidateExecution(cfg *config.ExecutionConfig) error {
+	switch cfg.Entry {
+	case EntryNextOpen, EntrySignalClose:
+	default:
+		return fmt.Errorf("unknown entry mode %q (use %q or %q)", cfg.Entry, EntryNextOpen, EntrySignalClose)
+	}
+	switch cfg.StopType {
+	case StopPercent:
+	case StopATR:
+		if cfg.ATRPeriod <= 0 {
+			return fmt.Errorf("atrPeriod must be positive, got %d", cfg.ATRPeriod)
+		}
+	default:
+		return fmt.Errorf("unknown stop type %q (use %q or %q)", cfg.StopType, StopPercent, StopATR)
+	}
+	if cfg.StopLoss < 0 || cfg.TakeProfit < 0 || cfg.MaxBars < 0 {
+		return fmt.Errorf("stopLoss, takeProfit and maxBars must not be negative")
+	}
+	if cfg.StopLoss == 0 && cfg.TakeProfit == 0 && cfg.MaxBars == 0 {
+		return fmt.Errorf("at least one of stopLoss, takeProfit or maxBars must be set")
+	}
+	if cfg.PositionSize <= 0 || cfg.InitialCapital <= 0 {
+		return fmt.Errorf("positionSize and initialCapital must be positive")
+	}
+	return nil
+}
+
+// simulateTrade opens a position for the signal raised on candles[idx] and
+// walks forward until a stop, target or time exit. It returns false when there
+// is no bar to enter on or the signal has no direction. atr may be nil when
+// the stop type is percent.
+func simulateTrade(cfg *config.ExecutionConfig, signal types.Signal, candles []types.Candle, idx int, atr []float64, duration time.Duration) (Trade, int, bool) {
+	if signal.Trend == "neutral" {
+		return Trade{}, idx, false
+	}
+
+	long := signal.Trend != "bearish"
+	side := "long"
+	dir := 1.0
+	if !long {
+		side = "short"
+		dir = -1.0
+	}
+
+	slip := cfg.SlippageBps / 10000
+	entryIdx := idx
+	entryPrice := candles[idx].Close
+	entryTime := time.UnixMilli(candles[idx].Timestamp).Add(duration)
+	if cfg.Entry == EntryNextOpen {
+		entryIdx = idx + 1
+		if entryIdx >= len(candles) {
+			return Trade{}, 0, false
+		}
+		entryPrice = candles[entryIdx].Open
+		entryTime = time.UnixMilli(candles[entryIdx].Timestamp)
+	}
+	// Market entry pays slippage against us
+	entryPrice *= 1 + dir*slip
+
+	stopDist, targetDist := cfg.StopLoss/100*entryPrice, cfg.TakeProfit/100*entryPrice
+	if cfg.StopType == StopATR {
+		if atr == nil || atr[idx] == 0 {
+			return Trade{}, 0, false
+		}
+		stopDist, targetDist = cfg.StopLoss*atr[idx], cfg.TakeProfit*atr[idx]
+	}
+
+	trade := Trade{
+		Symbol:     signal.Symbol,
+		Interval:   signal.Interval,
+		Pattern:    signal.Pattern,
+		Side:       side,
+		EntryTime:  entryTime.UTC(),
+		EntryPrice: entryPrice,
+	}
+	if stopDist > 0 {
+		trade.StopLoss = entryPrice - dir*stopDist
+	}
+	if targetDist > 0 {
+		trade.TakeProfit = entryPrice + dir*targetDist
+	}
+
+	// With next-open entry the entry bar itself can hit the stop or target
+	first := idx + 1
+	exitIdx := -1
+	exitFee := cfg.TakerFeeBps
+	for i := first; i < len(candles); i++ {
+		c := candles[i]
+		bars := i - idx
+
+		// When both levels are inside one bar we cannot know the order, so assume the stop hit first
+		if trade.StopLoss != 0 && ((long && c.Low <= trade.StopLoss) || (!long && c.High >= trade.StopLoss)) {
+			trade.ExitPrice = trade.StopLoss
+			if (long && c.Open < trade.StopLoss) || (!long && c.Open > trade.StopLoss) {
+				// Gapped through the stop
+				trade.ExitPrice = c.Open
+			}
+			trade.ExitPrice *= 1 - dir*slip
+			trade.ExitReason = ExitStopLoss
+			exitIdx = i
+			break
+		}
+		if trade.TakeProfit != 0 && ((long && c.High >= trade.TakeProfit) || (!long && c.Low <= trade.TakeProfit)) {
+			// Resting limit order fills at the target as maker
+			trade.ExitPrice = trade.TakeProfit
+			trade.ExitReason = ExitTakeProfit
+			exitFee = cfg.MakerFeeBps
+			exitIdx = i
+			break
+		}
+		if cfg.MaxBars > 0 && bars >= cfg.MaxBars {
+			trade.ExitPrice = c.Close * (1 - dir*slip)
+			trade.ExitReason = ExitTime
+			exitIdx = i
+			break
+		}
+	}
+
+	if exitIdx == -1 {
+		if len(candles) <= first {
+			return Trade{}, 0, false
+		}
+		exitIdx = len(candles) - 1
+		trade.ExitPrice = candles[exitIdx].Close * (1 - dir*slip)
+		trade.ExitReason = ExitEndOfData
+	}
+
+	trade.ExitTime = time.UnixMilli(candles[exitIdx].Timestamp).Add(duration).UTC()
+	if trade.ExitReason == ExitStopLoss || trade.ExitReason == ExitTakeProfit {
+		// Intrabar fill; the exact time is unknown so use the bar open
+		trade.ExitTime = time.UnixMilli(candles[exitIdx].Timestamp).UTC()
+	}
+	trade.Bars = exitIdx - idx
+	trade.FeesPct = (cfg.TakerFeeBps + exitFee) / 100
+	trade.ReturnPct = dir*(trade.ExitPrice-trade.EntryPrice)/trade.EntryPrice*100 - trade.FeesPct
+
+	return trade, exitIdx, true
+}
+
+// buildEquityCurve applies trades in exit order to a compounding account that
+// commits positionSize of equity to every trade, and fills in each trade's PnL.
+func buildEquityCurve(trades []Trade, initialCapital, positionSize float64) []EquityPoint {
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].ExitTime.Before(trades[j].ExitTime)
+	})
+
+	equity := initialCapital
+	curve := make([]EquityPoint, 0, len(trades))
+	for i := range trades {
+		trades[i].PnL = equity * positionSize * trades[i].ReturnPct / 100
+		equity += trades[i].PnL
+		curve = append(curve, EquityPoint{Time: trades[i].ExitTime, Equity: equity})
+	}
+	return curve
+}
+
+// computeTradeStats summarises a ledger and its equity curve
+func computeTradeStats(trades []Trade, curve []EquityPoint, initialCapital float64) TradeStats {
+	stats := TradeStats{Trades: len(trades), FinalEquity: initialCapital}
+	if len(trades) == 0 {
+		return stats
+	}
+
+	grossProfit, grossLoss := 0.0, 0.0
+	returns := make([]float64, len(trades))
+	for i, t := range trades {
+		switch {
+		case t.PnL > 0:
+			stats.Wins++
+			grossProfit += t.PnL
+		case t.PnL < 0:
+			stats.Losses++
+			grossLoss -= t.PnL
+		default:
+			stats.Breakeven++
+		}
+		returns[i] = t.ReturnPct
+		stats.AvgReturn += t.ReturnPct
+	}
+	stats.AvgReturn /= float64(len(trades))
+	if decided := stats.Wins + stats.Losses; decided > 0 {
+		stats.WinRate = float64(stats.Wins) / float64(decided) * 100
+	}
+
+	// Wins without a single loss make the profit factor infinite, which ranks
+	// above any finite value; a ledger with neither leaves it at zero
+	switch {
+	case grossLoss > 0:
+		stats.ProfitFactor = grossProfit / grossLoss
+	case grossProfit > 0:
+		stats.ProfitFactor = math.Inf(1)
+	}
+
+	peak := initialCapital
+	for _, p := range curve {
+		if p.Equity > peak {
+			peak = p.Equity
+		}
+		if dd := (peak - p.Equity) / peak * 100; dd > stats.MaxDrawdown {
+			stats.MaxDrawdown = dd
+		}
+	}
+	stats.FinalEquity = curve[len(curve)-1].Equity
+	stats.TotalReturn = (stats.FinalEquity - initialCapital) / initialCapital * 100
+
+	if len(returns) > 1 {
+		variance := 0.0
+		for _, r := range returns {
+			variance += (r - stats.AvgReturn) * (r - stats.AvgReturn)
+		}
+		stdDev := math.Sqrt(variance / float64(len(returns)-1))
+		if stdDev > 0 {
+			stats.Sharpe = stats.AvgReturn / stdDev
+		}
+	}
+
+	return stats
+}
@@ -0,0 +1,201 @@
+package backtester
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+func TestSimulateTrade(t *testing.T) {
+	cfg := &config.ExecutionConfig{
+		Entry:      EntryNextOpen,
+		StopType:   StopPercent,
+		StopLoss:   2,
+		TakeProfit: 4,
+		MaxBars:    3,
+	}
+
+	tests := []struct {
+		name       string
+		trend      string
+		forward    []types.Candle
+		wantReason string
+		wantReturn float64
+	}{
+		{
+			name:  "long hits take profit",
+			trend: "bullish",
+			forward: []types.Candle{
+				{Open: 100, High: 101, Low: 99, Close: 100.5},
+				{Open: 100.5, High: 105, Low: 100, Close: 104},
+			},
+			wantReason: ExitTakeProfit,
+			wantReturn: 4,
+		},
+		{
+			name:  "short hits stop loss",
+			trend: "bearish",
+			forward: []types.Candle{
+				{Open: 100, High: 103, Low: 99, Close: 102},
+			},
+			wantReason: ExitStopLoss,
+			wantReturn: -2,
+		},
+		{
+			name:  "stop assumed first when both levels hit in one bar",
+			trend: "bullish",
+			forward: []types.Candle{
+				{Open: 100, High: 105, Low: 97, Close: 100},
+			},
+			wantReason: ExitStopLoss,
+			wantReturn: -2,
+		},
+		{
+			name:  "time exit",
+			trend: "bullish",
+			forward: []types.Candle{
+				{Open: 100, High: 101, Low: 99, Close: 100},
+				{Open: 100, High: 101, Low: 99, Close: 100},
+				{Open: 100, High: 101, Low: 99, Close: 101},
+				{Open: 101, High: 110, Low: 99, Close: 108},
+			},
+			wantReason: ExitTime,
+			wantReturn: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candles := append([]types.Candle{{Open: 99, High: 100, Low: 98, Close: 100}}, tt.forward...)
+			for i := range candles {
+				candles[i].Timestamp = int64(i) * time.Hour.Milliseconds()
+			}
+
+			trade, _, ok := simulateTrade(cfg, types.Signal{Trend: tt.trend}, candles, 0, nil, time.Hour)
+			if !ok {
+				t.Fatal("expected a trade")
+			}
+			if trade.ExitReason != tt.wantReason {
+				t.Errorf("ExitReason = %s, want %s", trade.ExitReason, tt.wantReason)
+			}
+			if math.Abs(trade.ReturnPct-tt.wantReturn) > 1e-9 {
+				t.Errorf("ReturnPct = %v, want %v", trade.ReturnPct, tt.wantReturn)
+			}
+		})
+	}
+}
+
+func TestSimulateTrade_NeutralSignal(t *testing.T) {
+	cfg := &config.ExecutionConfig{Entry: EntryNextOpen, StopType: StopPercent, StopLoss: 2, MaxBars: 3}
+	candles := []types.Candle{
+		{Open: 99, High: 100, Low: 98, Close: 100},
+		{Open: 100, High: 101, Low: 99, Close: 100},
+	}
+
+	if _, _, ok := simulateTrade(cfg, types.Signal{Trend: "neutral"}, candles, 0, nil, time.Hour); ok {
+		t.Error("neutral signals should not be traded")
+	}
+}
+
+func TestSimulateTrade_FeesAndSlippage(t *testing.T) {
+	cfg := &config.ExecutionConfig{
+		Entry:       EntrySignalClose,
+		StopType:    StopPercent,
+		MaxBars:     1,
+		TakerFeeBps: 5,
+		SlippageBps: 10,
+	}
+	candles := []types.Candle{
+		{Open: 100, High: 100, Low: 100, Close: 100},
+		{Open: 100, High: 100, Low: 100, Close: 100},
+	}
+
+	trade, _, ok := simulateTrade(cfg, types.Signal{Trend: "bullish"}, candles, 0, nil, time.Hour)
+	if !ok {
+		t.Fatal("expected a trade")
+	}
+
+	// Buy at 100.1, sell at 99.9, pay 0.05% twice
+	want := (99.9-100.1)/100.1*100 - 0.1
+	if math.Abs(trade.ReturnPct-want) > 1e-9 {
+		t.Errorf("ReturnPct = %v, want %v", trade.ReturnPct, want)
+	}
+}
+
+func TestComputeTradeStats(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	trades := []Trade{
+		{ExitTime: base.Add(1 * time.Hour), ReturnPct: 10},
+		{ExitTime: base.Add(2 * time.Hour), ReturnPct: -20},
+		{ExitTime: base.Add(3 * time.Hour), ReturnPct: 10},
+	}
+
+	curve := buildEquityCurve(trades, 1000, 1)
+	stats := computeTradeStats(trades, curve, 1000)
+
+	// 1000 -> 1100 -> 880 -> 968
+	if math.Abs(stats.FinalEquity-968) > 1e-9 {
+		t.Errorf("FinalEquity = %v, want 968", stats.FinalEquity)
+	}
+	if math.Abs(stats.MaxDrawdown-20) > 1e-9 {
+		t.Errorf("MaxDrawdown = %v, want 20", stats.MaxDrawdown)
+	}
+	if stats.Wins != 2 || stats.Losses != 1 {
+		t.Errorf("Wins/Losses = %d/%d, want 2/1", stats.Wins, stats.Losses)
+	}
+	// Profit: 100 + 88 = 188, loss: 220
+	if math.Abs(stats.ProfitFactor-188.0/220.0) > 1e-9 {
+		t.Errorf("ProfitFactor = %v, want %v", stats.ProfitFactor, 188.0/220.0)
+	}
+}
+
+func TestComputeTradeStats_NoLosses(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	trades := []Trade{
+		{ExitTime: base.Add(1 * time.Hour), ReturnPct: 10},
+		{ExitTime: base.Add(2 * time.Hour), ReturnPct: 0},
+		{ExitTime: base.Add(3 * time.Hour), ReturnPct: 5},
+	}
+
+	curve := buildEquityCurve(trades, 1000, 1)
+	stats := computeTradeStats(trades, curve, 1000)
+
+	if stats.Wins != 2 || stats.Losses != 0 || stats.Breakeven != 1 {
+		t.Errorf("Wins/Losses/Breakeven = %d/%d/%d, want 2/0/1", stats.Wins, stats.Losses, stats.Breakeven)
+	}
+	if stats.WinRate != 100 {
+		t.Errorf("WinRate = %v, want 100", stats.WinRate)
+	}
+	if !math.IsInf(stats.ProfitFactor, 1) {
+		t.Errorf("ProfitFactor = %v, want +Inf", stats.ProfitFactor)
+	}
+
+	data, err := json.Marshal(stats)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded TradeStats
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if decoded != stats {
+		t.Errorf("round trip = %+v, want %+v", decoded, stats)
+	}
+}
+
+func TestComputeTradeStats_OnlyBreakeven(t *testing.T) {
+	trades := []Trade{{ExitTime: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), ReturnPct: 0}}
+
+	stats := computeTradeStats(trades, buildEquityCurve(trades, 1000, 1), 1000)
+
+	if stats.Breakeven != 1 || stats.Wins != 0 || stats.Losses != 0 {
+		t.Errorf("Wins/Losses/Breakeven = %d/%d/%d, want 0/0/1", stats.Wins, stats.Losses, stats.Breakeven)
+	}
+	if stats.WinRate != 0 || stats.ProfitFactor != 0 {
+		t.Errorf("WinRate/ProfitFactor = %v/%v, want 0/0", stats.WinRate, stats.ProfitFactor)
+	}
+}
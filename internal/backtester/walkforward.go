@@ -0,0 +1,112 @@
+package backtester
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WalkForwardWindow records the parameters chosen on one in-sample window and
+// how they performed on the out-of-sample window that follows it
+type WalkForwardWindow struct {
+	InSampleStart    time.Time  `json:"inSampleStart"`
+	InSampleEnd      time.Time  `json:"inSampleEnd"`
+	OutOfSampleStart time.Time  `json:"outOfSampleStart"`
+	OutOfSampleEnd   time.Time  `json:"outOfSampleEnd"`
+	Params           ParamSet   `json:"params"`
+	InSample         TradeStats `json:"inSample"`
+	OutOfSample      TradeStats `json:"outOfSample"`
+}
+
+// WalkForwardResult stitches every out-of-sample window into one ledger and equity curve
+type WalkForwardResult struct {
+	Interval    string              `json:"interval"`
+	Metric      string              `json:"metric"`
+	Windows     []WalkForwardWindow `json:"windows"`
+	Trades      []Trade             `json:"trades"`
+	EquityCurve []EquityPoint       `json:"equityCurve"`
+	Stats       TradeStats          `json:"stats"`
+}
+
+// WalkForward rolls an in-sample window of length inSample across [start, end)
+// in steps of outOfSample. On each step the grid point with the best in-sample
+// metric is chosen and then scored on the next outOfSample period. Trades are
+// closed at the end of their window, so the choice never sees the
+// out-of-sample bars and out-of-sample windows never overlap.
+func (o *Optimizer) WalkForward(ctx context.Context, symbols []string, interval string, grid []ParamSet, start, end time.Time, inSample, outOfSample time.Duration, metric string) (*WalkForwardResult, error) {
+	if inSample <= 0 || outOfSample <= 0 {
+		return nil, fmt.Errorf("in-sample and out-of-sample lengths must be positive")
+	}
+	if start.Add(inSample).After(end) || start.Add(inSample).Equal(end) {
+		return nil, fmt.Errorf("window from %s to %s is too short for a %v in-sample period",
+			start.Format(time.RFC3339), end.Format(time.RFC3339), inSample)
+	}
+	score, err := metricFunc(metric)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateExecution(&o.execution); err != nil {
+		return nil, fmt.Errorf("invalid execution config: %w", err)
+	}
+
+	maxRequired, err := o.maxRequired(grid)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := o.preload(ctx, symbols, interval, start, end, maxRequired)
+	if err != nil {
+		return nil, err
+	}
+	loaded, err := provider.GetSymbols(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WalkForwardResult{Interval: interval, Metric: metric}
+
+	for isStart := start; isStart.Add(inSample).Before(end); isStart = isStart.Add(outOfSample) {
+		window := WalkForwardWindow{
+			InSampleStart:    isStart,
+			InSampleEnd:      isStart.Add(inSample),
+			OutOfSampleStart: isStart.Add(inSample),
+			OutOfSampleEnd:   isStart.Add(inSample + outOfSample),
+		}
+		if window.OutOfSampleEnd.After(end) {
+			window.OutOfSampleEnd = end
+		}
+
+		candidates := make([]*Result, len(grid))
+		err := o.parallel(len(grid), func(i int) error {
+			var err error
+			candidates[i], err = o.runWindow(ctx, provider, grid[i], interval, loaded, window.InSampleStart, window.InSampleEnd, window.InSampleEnd)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		best := 0
+		for i := 1; i < len(candidates); i++ {
+			if better(score, *candidates[i].TradeStats, *candidates[best].TradeStats) {
+				best = i
+			}
+		}
+		window.Params = grid[best]
+		window.InSample = *candidates[best].TradeStats
+
+		oos, err := o.runWindow(ctx, provider, window.Params, interval, loaded, window.OutOfSampleStart, window.OutOfSampleEnd, window.OutOfSampleEnd)
+		if err != nil {
+			return nil, err
+		}
+		window.OutOfSample = *oos.TradeStats
+
+		result.Windows = append(result.Windows, window)
+		result.Trades = append(result.Trades, oos.Trades...)
+	}
+
+	result.EquityCurve = buildEquityCurve(result.Trades, o.execution.InitialCapital, o.execution.PositionSize)
+	result.Stats = computeTradeStats(result.Trades, result.EquityCurve, o.execution.InitialCapital)
+
+	return result, nil
+}
@@ -0,0 +1,140 @@
+package backtester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/strategies"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+func TestOptimizer_WalkForward(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(60 * time.Hour)
+
+	var prices [][2]float64
+	for i := 0; i < 80; i++ {
+		if (i/3)%2 == 0 {
+			prices = append(prices, [2]float64{100, 99})
+		} else {
+			prices = append(prices, [2]float64{99, 100})
+		}
+	}
+	provider := &mockProvider{candles: map[string][]types.Candle{
+		"BTCUSDT": makeCandles(start.Add(-5*time.Hour), prices...),
+	}}
+
+	factory := func(params ParamSet) (types.PatternMatcher, error) {
+		return strategies.NewConsecutiveCandles(int(params["minCount"])), nil
+	}
+	execution := config.ExecutionConfig{
+		Entry:          EntrySignalClose,
+		StopType:       StopPercent,
+		MaxBars:        1,
+		InitialCapital: 1000,
+		PositionSize:   1,
+	}
+
+	optimizer := NewOptimizer(provider, factory, execution)
+	grid := ExpandGrid(map[string][]float64{"minCount": {2, 3}})
+
+	result, err := optimizer.WalkForward(context.Background(), []string{"BTCUSDT"}, "1h", grid, start, end, 24*time.Hour, 12*time.Hour, "totalReturn")
+	if err != nil {
+		t.Fatalf("WalkForward() error = %v", err)
+	}
+
+	// In-sample windows start at 0h, 12h and 24h; the last one ends at 48h
+	if len(result.Windows) != 3 {
+		t.Fatalf("expected 3 windows, got %d", len(result.Windows))
+	}
+	for i, w := range result.Windows {
+		if !w.OutOfSampleStart.Equal(w.InSampleEnd) {
+			t.Errorf("window %d: out-of-sample should start where in-sample ends", i)
+		}
+		if i > 0 && !w.OutOfSampleStart.Equal(result.Windows[i-1].OutOfSampleEnd) {
+			t.Errorf("window %d: out-of-sample windows should be contiguous", i)
+		}
+		if w.Params == nil {
+			t.Errorf("window %d: no parameters chosen", i)
+		}
+	}
+	if last := result.Windows[len(result.Windows)-1]; !last.OutOfSampleEnd.Equal(end) {
+		t.Errorf("last out-of-sample window should end at %v, got %v", end, last.OutOfSampleEnd)
+	}
+
+	total := 0
+	for _, w := range result.Windows {
+		total += w.OutOfSample.Trades
+	}
+	if result.Stats.Trades != total || len(result.EquityCurve) != total {
+		t.Errorf("stitched stats have %d trades and %d equity points, want %d", result.Stats.Trades, len(result.EquityCurve), total)
+	}
+
+	if _, err := optimizer.WalkForward(context.Background(), []string{"BTCUSDT"}, "1h", grid, start, end, 100*time.Hour, 12*time.Hour, "totalReturn"); err == nil {
+		t.Error("expected error when in-sample period exceeds the window")
+	}
+}
+
+func TestOptimizer_WalkForward_ClosesTradesAtWindowEnd(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(48 * time.Hour)
+
+	// Two green candles every six bars, and a stop far below any low, so each
+	// trade is open until the data runs out
+	var prices [][2]float64
+	for i := 0; i < 60; i++ {
+		if i%6 < 2 {
+			prices = append(prices, [2]float64{100, 101})
+		} else {
+			prices = append(prices, [2]float64{101, 100})
+		}
+	}
+	provider := &mockProvider{candles: map[string][]types.Candle{
+		"BTCUSDT": makeCandles(start.Add(-5*time.Hour), prices...),
+	}}
+
+	factory := func(params ParamSet) (types.PatternMatcher, error) {
+		return strategies.NewConsecutiveCandles(int(params["minCount"])), nil
+	}
+	execution := config.ExecutionConfig{
+		Entry:          EntrySignalClose,
+		StopType:       StopPercent,
+		StopLoss:       50,
+		InitialCapital: 1000,
+		PositionSize:   1,
+	}
+
+	optimizer := NewOptimizer(provider, factory, execution)
+	grid := ExpandGrid(map[string][]float64{"minCount": {2}})
+
+	result, err := optimizer.WalkForward(context.Background(), []string{"BTCUSDT"}, "1h", grid, start, end, 24*time.Hour, 12*time.Hour, "totalReturn")
+	if err != nil {
+		t.Fatalf("WalkForward() error = %v", err)
+	}
+	if len(result.Windows) != 2 {
+		t.Fatalf("expected 2 windows, got %d", len(result.Windows))
+	}
+
+	// Every window's trades must close by its own end, at the latest on its
+	// last bar, or the stitched ledger double-counts the next window's bars
+	trades := result.Trades
+	for i, w := range result.Windows {
+		if w.OutOfSample.Trades == 0 {
+			t.Fatalf("window %d: expected out-of-sample trades", i)
+		}
+		window, rest := trades[:w.OutOfSample.Trades], trades[w.OutOfSample.Trades:]
+		for _, trade := range window {
+			if trade.ExitTime.After(w.OutOfSampleEnd) {
+				t.Errorf("window %d: trade entered at %v exits at %v, after the window end %v",
+					i, trade.EntryTime, trade.ExitTime, w.OutOfSampleEnd)
+			}
+		}
+		if last := window[len(window)-1]; last.ExitReason != ExitEndOfData || !last.ExitTime.Equal(w.OutOfSampleEnd) {
+			t.Errorf("window %d: last trade exits with %s at %v, want %s at %v",
+				i, last.ExitReason, last.ExitTime, ExitEndOfData, w.OutOfSampleEnd)
+		}
+		trades = rest
+	}
+}
@@ -1,86 +1,252 @@
 package bot
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/letieu/trade-bot/internal/config"
 	"github.com/letieu/trade-bot/internal/frontends/console"
 	"github.com/letieu/trade-bot/internal/frontends/telegram"
+	"github.com/letieu/trade-bot/internal/indicators"
+	"github.com/letieu/trade-bot/internal/market"
 	"github.com/letieu/trade-bot/internal/providers/bybit"
+	"github.com/letieu/trade-bot/internal/providers/candlestore"
+	"github.com/letieu/trade-bot/internal/scoring"
 	"github.com/letieu/trade-bot/internal/strategies"
 	"github.com/letieu/trade-bot/internal/types"
 )
 
+// sendTimeout bounds delivery of one batch of notifications. Sends are detached
+// from the scan context so signals found before a shutdown are still flushed.
+const sendTimeout = 30 * time.Second
+
+// Every signal carries RSI(14) and EMA(20) of the closes. Wilder's RSI depends on
+// its whole history, so more candles than the period are fetched to let it settle.
+const (
+	rsiPeriod        = 14
+	emaPeriod        = 20
+	indicatorHistory = 100
+)
+
 type Bot struct {
 	config     *config.Config
 	provider   types.MarketDataProvider
 	sender     types.NotificationSender
-	strategies []types.PatternMatcher
+	strategies []strategies.Configured
+	scorer     *scoring.Scorer
+
+	relativeStrength config.RelativeStrengthConfig
+	breadth          config.BreadthConfig
 }
 
 func NewBot(cfg *config.Config) *Bot {
-	bybitClient := bybit.NewClient(&cfg.Bybit)
+	client := bybit.NewClient(&cfg.Bybit)
+	var provider types.MarketDataProvider = client
+	if cfg.Bot.CacheCandles {
+		provider = candlestore.NewProvider(candlestore.NewStore(cfg.Backtest.DataPath), client)
+	}
 
 	var sender types.NotificationSender
 	var err error
 
 	switch cfg.Bot.Frontend {
 	case "console":
-		sender = console.NewBot()
+		consoleBot := console.NewBot()
+		consoleBot.MaxSignals = cfg.Scoring.MaxSignals
+		sender = consoleBot
 	case "telegram":
-		sender, err = telegram.NewBot(&cfg.Telegram)
+		sender, err = newTelegramSender(cfg)
 		if err != nil {
 			log.Fatalf("Failed to create telegram bot: %v", err)
 		}
 	default:
 		log.Printf("Unknown frontend '%s', defaulting to telegram", cfg.Bot.Frontend)
-		sender, err = telegram.NewBot(&cfg.Telegram)
+		sender, err = newTelegramSender(cfg)
 		if err != nil {
 			log.Fatalf("Failed to create telegram bot: %v", err)
 		}
 	}
 
-	// Initialize multiple strategies
-	strategies := []types.PatternMatcher{
-		strategies.NewThreeCandleReversal(),
-		strategies.NewConsecutiveCandles(3),
+	tradingBot, err := NewBotWithDeps(cfg, provider, sender)
+	if err != nil {
+		log.Fatalf("Failed to create bot: %v", err)
 	}
+	return tradingBot
+}
 
-	return &Bot{
-		config:     cfg,
-		provider:   bybitClient,
-		sender:     sender,
-		strategies: strategies,
+func newTelegramSender(cfg *config.Config) (*telegram.Bot, error) {
+	telegramBot, err := telegram.NewBot(&cfg.Telegram)
+	if err != nil {
+		return nil, err
 	}
+	telegramBot.MaxSignals = cfg.Scoring.MaxSignals
+	return telegramBot, nil
 }
 
-// NewBotWithDeps allows creating a bot with injected dependencies (useful for testing)
-func NewBotWithDeps(cfg *config.Config, provider types.MarketDataProvider, sender types.NotificationSender) *Bot {
-	strategies := []types.PatternMatcher{
-		strategies.NewThreeCandleReversal(),
-		strategies.NewConsecutiveCandles(3),
+// NewBotWithDeps allows creating a bot with injected dependencies (useful for
+// testing). It fails on a bad config so typos surface at startup rather than
+// as a silent lack of signals.
+func NewBotWithDeps(cfg *config.Config, provider types.MarketDataProvider, sender types.NotificationSender) (*Bot, error) {
+	built, err := buildStrategies(cfg)
+	if err != nil {
+		return nil, err
+	}
+	relativeStrength, err := buildRelativeStrength(cfg)
+	if err != nil {
+		return nil, err
+	}
+	breadth, err := buildBreadth(cfg)
+	if err != nil {
+		return nil, err
+	}
+	scorer, err := scoring.NewScorer(cfg.Scoring)
+	if err != nil {
+		return nil, err
 	}
+
 	return &Bot{
 		config:     cfg,
 		provider:   provider,
 		sender:     sender,
-		strategies: strategies,
+		strategies: built,
+		scorer:     scorer,
+
+		relativeStrength: relativeStrength,
+		breadth:          breadth,
+	}, nil
+}
+
+// buildBreadth fills the breadth defaults and checks them
+func buildBreadth(cfg *config.Config) (config.BreadthConfig, error) {
+	breadth := cfg.Breadth
+	if breadth.HighLowBars == 0 {
+		breadth.HighLowBars = 20
+	}
+
+	if !breadth.Enabled {
+		return breadth, nil
+	}
+	if breadth.HighLowBars < 1 {
+		return breadth, fmt.Errorf("invalid breadth config: highLowBars must be at least 1, got %d", breadth.HighLowBars)
+	}
+	if err := checkEnabled(breadth.Intervals, cfg.Bot.EnabledIntervals); err != nil {
+		return breadth, fmt.Errorf("invalid breadth config: %w", err)
+	}
+	return breadth, nil
+}
+
+// buildRelativeStrength fills the relative strength defaults and checks them
+func buildRelativeStrength(cfg *config.Config) (config.RelativeStrengthConfig, error) {
+	rs := cfg.RelativeStrength
+	defaults := config.DefaultRelativeStrength()
+	if len(rs.Lookbacks) == 0 {
+		rs.Lookbacks = defaults.Lookbacks
+	}
+	if rs.Top == 0 {
+		rs.Top = defaults.Top
+	}
+	if rs.Benchmark == "" {
+		rs.Benchmark = defaults.Benchmark
+	}
+
+	if !rs.Enabled {
+		return rs, nil
+	}
+	for _, lookback := range rs.Lookbacks {
+		if lookback < 1 {
+			return rs, fmt.Errorf("invalid relativeStrength config: lookbacks must be at least 1, got %d", lookback)
+		}
+	}
+	if rs.Top < 1 {
+		return rs, fmt.Errorf("invalid relativeStrength config: top must be at least 1, got %d", rs.Top)
+	}
+	if err := checkEnabled(rs.Intervals, cfg.Bot.EnabledIntervals); err != nil {
+		return rs, fmt.Errorf("invalid relativeStrength config: %w", err)
+	}
+	return rs, nil
+}
+
+// checkEnabled rejects report intervals that are never scanned, since the
+// report would silently never run on them
+func checkEnabled(intervals, enabled []string) error {
+	for _, interval := range intervals {
+		if !slices.Contains(enabled, interval) {
+			return fmt.Errorf("interval %q is not in bot.enabledIntervals", interval)
+		}
+	}
+	return nil
+}
+
+// buildStrategies creates the configured strategies
+func buildStrategies(cfg *config.Config) ([]strategies.Configured, error) {
+	strategyConfigs := cfg.Strategies
+	if len(strategyConfigs) == 0 {
+		strategyConfigs = config.DefaultStrategies()
+	}
+
+	built, err := strategies.DefaultRegistry().BuildAll(strategyConfigs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid strategies config: %w", err)
+	}
+	if len(built) == 0 {
+		return nil, fmt.Errorf("invalid strategies config: no strategy is enabled")
+	}
+
+	// Confluence must look at a longer timeframe than every interval it runs on
+	for _, s := range built {
+		for _, interval := range cfg.Bot.EnabledIntervals {
+			if s.Confluence.Mode == "" || !s.RunsOn(interval) {
+				continue
+			}
+			higher := s.ConfluenceInterval(interval)
+			if !longer(higher, interval) {
+				return nil, fmt.Errorf("invalid strategies config: strategy %q: confluence on %s needs a longer interval, got %q", s.Name, interval, higher)
+			}
+		}
+	}
+	return built, nil
+}
+
+// longer reports whether interval a is a valid interval longer than b
+func longer(a, b string) bool {
+	da, err := types.ParseInterval(a)
+	if err != nil {
+		return false
+	}
+	db, err := types.ParseInterval(b)
+	return err == nil && da > db
+}
+
+// strategiesFor returns the strategies that run on interval
+func (b *Bot) strategiesFor(interval string) []strategies.Configured {
+	var configured []strategies.Configured
+	for _, s := range b.strategies {
+		if s.RunsOn(interval) {
+			configured = append(configured, s)
+		}
 	}
+	return configured
 }
 
-func (b *Bot) Start() error {
+// Start runs the scan loops until ctx is cancelled, then waits for in-flight
+// scans to finish sending what they found
+func (b *Bot) Start(ctx context.Context) error {
 	strategyNames := make([]string, len(b.strategies))
 	for i, s := range b.strategies {
-		strategyNames[i] = s.GetName()
+		strategyNames[i] = s.Matcher.GetName()
+		if len(s.Intervals) > 0 {
+			strategyNames[i] += fmt.Sprintf(" %v", s.Intervals)
+		}
 	}
 	log.Printf("Starting trading bot with %d strategies: %v", len(b.strategies), strategyNames)
 
 	if b.config.Bot.RunOnce {
 		log.Println("Running in one-time mode")
-		return b.scan()
+		return b.scan(ctx)
 	}
 
 	var wg sync.WaitGroup
@@ -88,15 +254,16 @@ func (b *Bot) Start() error {
 		wg.Add(1)
 		go func(intervalStr string) {
 			defer wg.Done()
-			b.runIntervalLoop(intervalStr)
+			b.runIntervalLoop(ctx, intervalStr)
 		}(interval)
 	}
 
 	wg.Wait()
+	log.Println("All scan loops stopped")
 	return nil
 }
 
-func (b *Bot) runIntervalLoop(intervalStr string) {
+func (b *Bot) runIntervalLoop(ctx context.Context, intervalStr string) {
 	duration, err := types.ParseInterval(intervalStr)
 	if err != nil {
 		log.Printf("[%s] Failed to parse interval: %v", intervalStr, err)
@@ -113,26 +280,38 @@ func (b *Bot) runIntervalLoop(intervalStr string) {
 		log.Printf("[%s] Next scan in %v at %v", intervalStr, sleepDuration.Round(time.Second), next.Local().Format("15:04:05"))
 
 		timer := time.NewTimer(sleepDuration)
-		<-timer.C
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Printf("[%s] Stopping scan loop", intervalStr)
+			return
+		case <-timer.C:
+		}
 
 		log.Printf("[%s] Starting scan...", intervalStr)
-		if err := b.scanSpecificInterval(intervalStr); err != nil {
+		if err := b.scanSpecificInterval(ctx, intervalStr); err != nil {
 			log.Printf("[%s] Error during scan: %v", intervalStr, err)
 		}
 	}
 }
 
-func (b *Bot) scanSpecificInterval(interval string) error {
-	symbols, err := b.provider.GetSymbols()
+func (b *Bot) scanSpecificInterval(ctx context.Context, interval string) error {
+	symbols, err := b.provider.GetSymbols(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get symbols: %w", err)
 	}
 
-	signals := b.scanInterval(symbols, interval)
+	result := b.scanInterval(ctx, symbols, interval)
+	signals := result.signals
+	if ctx.Err() != nil {
+		log.Printf("[%s] Scan interrupted by shutdown", interval)
+	}
+
+	b.notifyReports(ctx, result)
 
 	if len(signals) > 0 {
 		log.Printf("[%s] Found %d signals, sending result", interval, len(signals))
-		if err := b.sender.SendSignals(signals); err != nil {
+		if err := b.notify(ctx, signals); err != nil {
 			return fmt.Errorf("failed to send signals: %w", err)
 		}
 	} else {
@@ -142,8 +321,8 @@ func (b *Bot) scanSpecificInterval(interval string) error {
 	return nil
 }
 
-func (b *Bot) scan() error {
-	symbols, err := b.provider.GetSymbols()
+func (b *Bot) scan(ctx context.Context) error {
+	symbols, err := b.provider.GetSymbols(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get symbols: %w", err)
 	}
@@ -151,28 +330,28 @@ func (b *Bot) scan() error {
 	log.Printf("Scanning %d symbols for patterns", len(symbols))
 
 	var wg sync.WaitGroup
-	signalsChan := make(chan []types.Signal, len(b.config.Bot.EnabledIntervals))
+	resultsChan := make(chan scanResult, len(b.config.Bot.EnabledIntervals))
 
 	for _, interval := range b.config.Bot.EnabledIntervals {
 		wg.Add(1)
 		go func(intervalStr string) {
 			defer wg.Done()
-			signals := b.scanInterval(symbols, intervalStr)
-			signalsChan <- signals
+			resultsChan <- b.scanInterval(ctx, symbols, intervalStr)
 		}(interval)
 	}
 
 	wg.Wait()
-	close(signalsChan)
+	close(resultsChan)
 
 	allSignals := make([]types.Signal, 0)
-	for signals := range signalsChan {
-		allSignals = append(allSignals, signals...)
+	for result := range resultsChan {
+		allSignals = append(allSignals, result.signals...)
+		b.notifyReports(ctx, result)
 	}
 
 	if len(allSignals) > 0 {
 		log.Printf("Found %d signals, sending result", len(allSignals))
-		if err := b.sender.SendSignals(allSignals); err != nil {
+		if err := b.notify(ctx, allSignals); err != nil {
 			return fmt.Errorf("failed to send signals: %w", err)
 		}
 	} else {
@@ -182,8 +361,70 @@ func (b *Bot) scan() error {
 	return nil
 }
 
-func (b *Bot) scanInterval(symbols []string, interval string) []types.Signal {
+// notify sends signals scoring at least scoring.minScore on a context that
+// outlives ctx, so a shutdown that arrives mid-scan still flushes what was
+// already found
+func (b *Bot) notify(ctx context.Context, signals []types.Signal) error {
+	if kept := scoring.Filter(signals, b.config.Scoring.MinScore); len(kept) < len(signals) {
+		log.Printf("Dropped %d signals scoring below %.0f", len(signals)-len(kept), b.config.Scoring.MinScore)
+		signals = kept
+	}
+	if len(signals) == 0 {
+		return nil
+	}
+
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+	defer cancel()
+
+	return b.sender.SendSignals(sendCtx, signals)
+}
+
+// scanResult is what one scan of an interval found
+type scanResult struct {
+	signals []types.Signal
+	movers  *types.MoverReport // nil unless relative strength runs on the interval
+	breadth *types.Breadth     // nil unless breadth runs on the interval
+}
+
+// notifyReports sends the cross-symbol reports of a scan, like notify: the
+// market breadth first, as context for the signals, then the movers. Reports
+// are best effort, so a failed one is logged and never holds back the scan's
+// signals.
+func (b *Bot) notifyReports(ctx context.Context, result scanResult) {
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+	defer cancel()
+
+	if breadth := result.breadth; breadth != nil {
+		log.Printf("[%s] Market breadth of %d symbols, sending summary", breadth.Interval, breadth.Symbols)
+		if err := b.sender.SendBreadth(sendCtx, *breadth); err != nil {
+			log.Printf("[%s] Failed to send breadth: %v", breadth.Interval, err)
+		}
+	}
+	if movers := result.movers; movers != nil {
+		log.Printf("[%s] Ranked %d symbols by relative strength, sending movers", movers.Interval, movers.Symbols)
+		if err := b.sender.SendMovers(sendCtx, *movers); err != nil {
+			log.Printf("[%s] Failed to send movers: %v", movers.Interval, err)
+		}
+	}
+}
+
+func (b *Bot) scanInterval(ctx context.Context, symbols []string, interval string) scanResult {
+	configured := b.strategiesFor(interval)
+	rsHistory := b.relativeStrengthHistory(interval)
+	breadthHistory := b.breadthHistory(interval)
+	if len(configured) == 0 && rsHistory == 0 && breadthHistory == 0 {
+		return scanResult{}
+	}
+
+	// Cross-symbol reports reuse the strategies' candles, fetched deep enough for both
+	collect := rsHistory > 0 || breadthHistory > 0
+	limit := max(rsHistory, breadthHistory)
+	if len(configured) > 0 {
+		limit = max(limit, requiredCandles(configured), indicatorHistory)
+	}
+
 	var signals []types.Signal
+	series := make(map[string][]types.Candle) // closed candles per symbol, for the cross-symbol reports
 	var mu sync.Mutex
 
 	semaphore := make(chan struct{}, b.config.Bot.MaxConcurrency)
@@ -202,37 +443,91 @@ func (b *Bot) scanInterval(symbols []string, interval string) []types.Signal {
 			go func(sym string) {
 				defer wg.Done()
 
-				semaphore <- struct{}{}
+				select {
+				case semaphore <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
 				defer func() { <-semaphore }()
 
+				candles := b.closedCandles(ctx, sym, interval, limit)
+				if candles == nil {
+					return
+				}
+
 				// Check all strategies for this symbol
-				symbolSignals := b.checkSymbol(sym, interval)
-				if len(symbolSignals) > 0 {
-					mu.Lock()
-					signals = append(signals, symbolSignals...)
-					mu.Unlock()
+				var symbolSignals []types.Signal
+				if len(configured) > 0 {
+					symbolSignals = b.checkSymbol(ctx, sym, interval, configured, candles)
+				}
+
+				mu.Lock()
+				signals = append(signals, symbolSignals...)
+				if collect {
+					series[sym] = candles
 				}
+				mu.Unlock()
 			}(symbol)
 		}
-
-		time.Sleep(300 * time.Millisecond)
 	}
 
 	wg.Wait()
-	return signals
-}
 
-func (b *Bot) checkSymbol(symbol, interval string) []types.Signal {
-	// Get the maximum required candles across all strategies
-	maxRequired := 0
-	for _, strategy := range b.strategies {
-		if req := strategy.GetRequiredCandles(); req > maxRequired {
-			maxRequired = req
+	// An interrupted scan has only part of the market
+	result := scanResult{signals: signals}
+	if ctx.Err() != nil {
+		return result
+	}
+	if rsHistory > 0 {
+		result.movers = market.RelativeStrength(interval, series, b.relativeStrength)
+		if result.movers != nil {
+			result.movers.Timestamp = time.Now()
 		}
 	}
+	if breadthHistory > 0 {
+		result.breadth = market.Breadth(interval, series, b.breadth.HighLowBars)
+		if result.breadth != nil {
+			result.breadth.Timestamp = time.Now()
+		}
+	}
+	return result
+}
+
+// breadthHistory is the candles breadth needs on interval, or 0 when it does
+// not run there
+func (b *Bot) breadthHistory(interval string) int {
+	if !b.breadth.Enabled {
+		return 0
+	}
+	if len(b.breadth.Intervals) > 0 && !slices.Contains(b.breadth.Intervals, interval) {
+		return 0
+	}
+	return max(market.BreadthHistory, b.breadth.HighLowBars+1) + 1
+}
+
+// relativeStrengthHistory is the candles relative strength needs on interval,
+// or 0 when it does not run there. One extra candle covers the one still
+// forming, which is dropped.
+func (b *Bot) relativeStrengthHistory(interval string) int {
+	rs := b.relativeStrength
+	if !rs.Enabled {
+		return 0
+	}
+	if len(rs.Intervals) > 0 && !slices.Contains(rs.Intervals, interval) {
+		return 0
+	}
+	return slices.Max(rs.Lookbacks) + 2
+}
 
-	candles, err := b.provider.GetCandles(symbol, interval, maxRequired, b.config.Bot.TargetTime)
+// closedCandles fetches up to limit candles of symbol, leaving out the one
+// still forming. It returns nil when the fetch fails.
+func (b *Bot) closedCandles(ctx context.Context, symbol, interval string, limit int) []types.Candle {
+	candles, err := b.provider.GetCandles(ctx, symbol, interval, limit, b.config.Bot.TargetTime)
 	if err != nil {
+		if ctx.Err() != nil {
+			// Cancelled by shutdown, not worth a log line per symbol
+			return nil
+		}
 		log.Printf("Failed to get candles for %s: %v", symbol, err)
 		return nil
 	}
@@ -258,56 +553,104 @@ func (b *Bot) checkSymbol(symbol, interval string) []types.Signal {
 		}
 	}
 
+	return candles
+}
+
+// requiredCandles is the most candles any of configured needs
+func requiredCandles(configured []strategies.Configured) int {
+	maxRequired := 0
+	for _, strategy := range configured {
+		if req := strategy.Matcher.GetRequiredCandles(); req > maxRequired {
+			maxRequired = req
+		}
+	}
+	return maxRequired
+}
+
+func (b *Bot) checkSymbol(ctx context.Context, symbol, interval string, configured []strategies.Configured, candles []types.Candle) []types.Signal {
+	maxRequired := requiredCandles(configured)
+
 	if len(candles) < 4 {
 		// Not enough closed candles
 		return nil
 	}
 
+	rsi := indicators.NewRSI(rsiPeriod)
+	ema := indicators.NewEMA(emaPeriod)
+	for _, c := range candles {
+		rsi.Update(c)
+		ema.Update(c)
+	}
+
+	// Strategies see only the window they asked for, as in the backtester
+	window := candles
+	if len(window) > maxRequired {
+		window = window[len(window)-maxRequired:]
+	}
+
 	var signals []types.Signal
+	trends := make(map[string]string) // higher timeframe trends fetched for this symbol
 
 	// Check all strategies
-	for _, strategy := range b.strategies {
-		matched, err := strategy.Match(candles)
+	for _, strategy := range configured {
+		matcher := strategy.Matcher
+		match, err := matcher.Match(window)
 		if err != nil {
-			log.Printf("Error matching pattern %s for %s: %v", strategy.GetName(), symbol, err)
+			log.Printf("Error matching pattern %s for %s: %v", matcher.GetName(), symbol, err)
 			continue
 		}
 
-		if !matched {
+		if match == nil {
 			continue
 		}
 
-		lastCandles := candles[len(candles)-4:]
-		lastCandle := candles[len(candles)-1]
-
-		// Get metadata from strategy (e.g., consecutive count)
-		metadata := strategy.GetMetadata(candles)
-		consecutiveCount := 0
-		if count, ok := metadata["consecutive_count"].(int); ok {
-			consecutiveCount = count
-		}
-
-		signal := types.Signal{
-			Symbol:           symbol,
-			Interval:         interval,
-			Pattern:          strategy.GetName(),
-			Trend:            "bullish",
-			Price:            lastCandle.Close,
-			RSI:              0,
-			EMA:              0,
-			Volume:           lastCandle.Volume,
-			Timestamp:        time.Now(),
-			Candles:          lastCandles,
-			ConsecutiveCount: consecutiveCount,
-		}
-
-		if lastCandles[len(lastCandles)-1].Color() == types.ColorRed {
-			signal.Trend = "bearish"
+		signal := types.NewSignal(symbol, interval, matcher.GetName(), match, time.Now())
+		signal.RSI = rsi.Value()
+		signal.EMA = ema.Value()
+
+		if higher := strategy.ConfluenceInterval(interval); higher != "" {
+			trend := b.trend(ctx, symbol, higher, strategy.Confluence.EMAPeriod, trends)
+			signal.Confluence = &types.Confluence{
+				Interval: higher,
+				Trend:    trend,
+				Agrees:   trend != "" && trend == signal.Trend,
+			}
+			if strategy.Confluence.Mode == strategies.ConfluenceRequire && !signal.Confluence.Agrees {
+				log.Printf("Signal dropped: %s %s %s, %s trend %q does not confirm %s", symbol, interval, signal.Pattern, higher, trend, signal.Trend)
+				continue
+			}
 		}
 
 		log.Printf("Signal found: %s %s %s", symbol, interval, signal.Pattern)
 		signals = append(signals, signal)
 	}
 
+	if len(signals) > 0 {
+		higherTrend := ""
+		if higher := types.HigherInterval(interval); b.scorer.HigherTimeframe() && higher != "" {
+			higherTrend = b.trend(ctx, symbol, higher, b.scorer.TrendPeriod(), trends)
+		}
+		for i := range signals {
+			b.scorer.Score(&signals[i], candles, higherTrend)
+		}
+	}
+
 	return signals
 }
+
+// trend is the symbol's direction on interval, its close against EMA(period),
+// or "" when unavailable. Results are kept in cache so each timeframe is
+// fetched at most once per symbol and scan, and only for symbols with signals.
+func (b *Bot) trend(ctx context.Context, symbol, interval string, period int, cache map[string]string) string {
+	key := fmt.Sprintf("%s/%d", interval, period)
+	if trend, ok := cache[key]; ok {
+		return trend
+	}
+
+	// Three periods of closed history let the EMA settle; the forming bar is
+	// not a close and could flip the trend before it ends
+	trend := scoring.EMATrend(b.closedCandles(ctx, symbol, interval, 3*period+1), period)
+
+	cache[key] = trend
+	return trend
+}
@@ -12,6 +12,13 @@ type Config struct {
 	Telegram TelegramConfig `mapstructure:"telegram"`
 	Bybit    BybitConfig    `mapstructure:"bybit"`
 	Bot      BotConfig      `mapstructure:"bot"`
+	Backtest BacktestConfig `mapstructure:"backtest"`
+
+	Strategies []StrategyConfig `mapstructure:"strategies"`
+	Scoring    ScoringConfig    `mapstructure:"scoring"`
+
+	RelativeStrength RelativeStrengthConfig `mapstructure:"relativeStrength"`
+	Breadth          BreadthConfig          `mapstructure:"breadth"`
 }
 
 type TelegramConfig struct {
@@ -27,12 +34,136 @@ type BybitConfig struct {
 }
 
 type BotConfig struct {
-	BatchSize        int           `mapstructure:"batchSize"`
-	MaxConcurrency   int           `mapstructure:"maxConcurrency"`
-	EnabledIntervals []string      `mapstructure:"enabledIntervals"`
-	Frontend         string        `mapstructure:"frontend"`
-	RunOnce          bool          `mapstructure:"runOnce"`
-	TargetTime       int64         `mapstructure:"targetTime"`
+	BatchSize        int      `mapstructure:"batchSize"`
+	MaxConcurrency   int      `mapstructure:"maxConcurrency"`
+	EnabledIntervals []string `mapstructure:"enabledIntervals"`
+	Frontend         string   `mapstructure:"frontend"`
+	RunOnce          bool     `mapstructure:"runOnce"`
+	TargetTime       int64    `mapstructure:"targetTime"`
+	CacheCandles     bool     `mapstructure:"cacheCandles"` // Serve candles from the store under backtest.dataPath
+}
+
+type BacktestConfig struct {
+	StartTime   string `mapstructure:"startTime"` // RFC3339
+	EndTime     string `mapstructure:"endTime"`   // RFC3339
+	DataPath    string `mapstructure:"dataPath"`
+	SaveResults bool   `mapstructure:"saveResults"`
+	ResultsPath string `mapstructure:"resultsPath"`
+
+	Execution ExecutionConfig `mapstructure:"execution"`
+}
+
+// ExecutionConfig controls how backtest signals are turned into simulated trades
+type ExecutionConfig struct {
+	Enabled        bool    `mapstructure:"enabled"`
+	Entry          string  `mapstructure:"entry"`      // "nextOpen" or "signalClose"
+	StopType       string  `mapstructure:"stopType"`   // "percent" or "atr"
+	StopLoss       float64 `mapstructure:"stopLoss"`   // percent, or ATR multiple
+	TakeProfit     float64 `mapstructure:"takeProfit"` // percent, or ATR multiple
+	ATRPeriod      int     `mapstructure:"atrPeriod"`
+	MaxBars        int     `mapstructure:"maxBars"` // time-based exit, 0 = disabled
+	TakerFeeBps    float64 `mapstructure:"takerFeeBps"`
+	MakerFeeBps    float64 `mapstructure:"makerFeeBps"`
+	SlippageBps    float64 `mapstructure:"slippageBps"`
+	InitialCapital float64 `mapstructure:"initialCapital"`
+	PositionSize   float64 `mapstructure:"positionSize"` // fraction of equity per trade
+}
+
+// StrategyConfig selects one registered strategy and its parameters
+type StrategyConfig struct {
+	Name      string                 `mapstructure:"name"`
+	Enabled   *bool                  `mapstructure:"enabled"`   // defaults to true
+	Intervals []string               `mapstructure:"intervals"` // empty = every enabled interval
+	Params    map[string]interface{} `mapstructure:"params"`
+
+	Confluence ConfluenceConfig `mapstructure:"confluence"`
+	Transform  TransformConfig  `mapstructure:"transform"`
+}
+
+// TransformConfig converts a strategy's candles into another bar series
+// before it sees them
+type TransformConfig struct {
+	Type      string  `mapstructure:"type"`      // "" (raw candles), "heikinAshi" or "renko"
+	Brick     float64 `mapstructure:"brick"`     // Renko brick size in price, overrides BrickATR when set
+	BrickATR  float64 `mapstructure:"brickATR"`  // Renko brick size in ATRs from the anchor, fixed until the anchor moves; defaults to 1
+	ATRPeriod int     `mapstructure:"atrPeriod"` // Renko ATR period, defaults to 14
+	History   int     `mapstructure:"history"`   // Renko candles between anchor moves, on blocks aligned to Unix time; defaults to atrPeriod + 10 per brick needed
+}
+
+// ConfluenceConfig checks a strategy's signals against the trend on a higher
+// timeframe: the close above its EMA is bullish, below is bearish
+type ConfluenceConfig struct {
+	Mode      string `mapstructure:"mode"`      // "" (off), "tag" or "require"
+	Interval  string `mapstructure:"interval"`  // defaults to the next interval up
+	EMAPeriod int    `mapstructure:"emaPeriod"` // defaults to 50
+}
+
+func (s StrategyConfig) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
+}
+
+// DefaultStrategies are used when the config has no strategies section
+func DefaultStrategies() []StrategyConfig {
+	return []StrategyConfig{
+		{Name: "reversal", Params: map[string]interface{}{"count": 3}},
+		{Name: "consecutive", Params: map[string]interface{}{"minCount": 3}},
+	}
+}
+
+// ScoringConfig controls how signals are scored, ordered and capped
+type ScoringConfig struct {
+	Weights         ScoreWeights `mapstructure:"weights"`
+	MinScore        float64      `mapstructure:"minScore"`        // drop signals below this score (0-100)
+	MaxSignals      int          `mapstructure:"maxSignals"`      // per message, highest scores first; 0 = no cap
+	HigherTimeframe bool         `mapstructure:"higherTimeframe"` // fetch the next interval up for the trend component
+	TrendPeriod     int          `mapstructure:"trendPeriod"`     // EMA period on the higher timeframe
+	LiquidityLow    float64      `mapstructure:"liquidityLow"`    // average quote turnover per candle scoring 0
+	LiquidityHigh   float64      `mapstructure:"liquidityHigh"`   // average quote turnover per candle scoring 1
+}
+
+// ScoreWeights are the relative weights of the score components
+type ScoreWeights struct {
+	Pattern   float64 `mapstructure:"pattern"`
+	Volume    float64 `mapstructure:"volume"`
+	RSI       float64 `mapstructure:"rsi"`
+	Trend     float64 `mapstructure:"trend"`
+	Liquidity float64 `mapstructure:"liquidity"`
+}
+
+func DefaultScoring() ScoringConfig {
+	return ScoringConfig{
+		Weights:         ScoreWeights{Pattern: 0.3, Volume: 0.2, RSI: 0.15, Trend: 0.2, Liquidity: 0.15},
+		HigherTimeframe: true,
+		TrendPeriod:     50,
+		LiquidityLow:    1e5,
+		LiquidityHigh:   1e8,
+	}
+}
+
+// RelativeStrengthConfig ranks every symbol's returns against the others and
+// a benchmark after each scan, and reports the top and bottom movers
+type RelativeStrengthConfig struct {
+	Enabled   bool     `mapstructure:"enabled"`
+	Intervals []string `mapstructure:"intervals"` // empty means every enabled interval
+	Lookbacks []int    `mapstructure:"lookbacks"` // return periods in candles
+	Top       int      `mapstructure:"top"`       // movers reported at each end
+	Benchmark string   `mapstructure:"benchmark"` // symbol every return is compared with
+}
+
+func DefaultRelativeStrength() RelativeStrengthConfig {
+	return RelativeStrengthConfig{
+		Lookbacks: []int{1, 6, 24},
+		Top:       5,
+		Benchmark: "BTCUSDT",
+	}
+}
+
+// BreadthConfig sends a market overview built from every symbol's candles
+// after each scan
+type BreadthConfig struct {
+	Enabled     bool     `mapstructure:"enabled"`
+	Intervals   []string `mapstructure:"intervals"`   // empty means every enabled interval
+	HighLowBars int      `mapstructure:"highLowBars"` // candles a new high or low must exceed, defaults to 20
 }
 
 func Load(configFile string) *Config {
@@ -55,6 +186,49 @@ func Load(configFile string) *Config {
 	v.SetDefault("bot.maxConcurrency", 5)
 	v.SetDefault("bot.enabledIntervals", []string{"1h", "4h", "1d"})
 	v.SetDefault("bot.frontend", "telegram")
+	v.SetDefault("bot.cacheCandles", false)
+
+	// Set defaults for backtest config
+	v.SetDefault("backtest.dataPath", "./data")
+	v.SetDefault("backtest.saveResults", true)
+	v.SetDefault("backtest.resultsPath", "./results")
+	v.SetDefault("backtest.execution.enabled", false)
+	v.SetDefault("backtest.execution.entry", "nextOpen")
+	v.SetDefault("backtest.execution.stopType", "percent")
+	v.SetDefault("backtest.execution.stopLoss", 2.0)
+	v.SetDefault("backtest.execution.takeProfit", 4.0)
+	v.SetDefault("backtest.execution.atrPeriod", 14)
+	v.SetDefault("backtest.execution.maxBars", 10)
+	v.SetDefault("backtest.execution.takerFeeBps", 5.5)
+	v.SetDefault("backtest.execution.makerFeeBps", 2.0)
+	v.SetDefault("backtest.execution.slippageBps", 2.0)
+	v.SetDefault("backtest.execution.initialCapital", 10000.0)
+	v.SetDefault("backtest.execution.positionSize", 0.1)
+
+	// Set defaults for scoring config
+	scoring := DefaultScoring()
+	v.SetDefault("scoring.weights.pattern", scoring.Weights.Pattern)
+	v.SetDefault("scoring.weights.volume", scoring.Weights.Volume)
+	v.SetDefault("scoring.weights.rsi", scoring.Weights.RSI)
+	v.SetDefault("scoring.weights.trend", scoring.Weights.Trend)
+	v.SetDefault("scoring.weights.liquidity", scoring.Weights.Liquidity)
+	v.SetDefault("scoring.minScore", 0)
+	v.SetDefault("scoring.maxSignals", 0)
+	v.SetDefault("scoring.higherTimeframe", scoring.HigherTimeframe)
+	v.SetDefault("scoring.trendPeriod", scoring.TrendPeriod)
+	v.SetDefault("scoring.liquidityLow", scoring.LiquidityLow)
+	v.SetDefault("scoring.liquidityHigh", scoring.LiquidityHigh)
+
+	// Set defaults for relative strength config
+	relativeStrength := DefaultRelativeStrength()
+	v.SetDefault("relativeStrength.enabled", false)
+	v.SetDefault("relativeStrength.lookbacks", relativeStrength.Lookbacks)
+	v.SetDefault("relativeStrength.top", relativeStrength.Top)
+	v.SetDefault("relativeStrength.benchmark", relativeStrength.Benchmark)
+
+	// Set defaults for breadth config
+	v.SetDefault("breadth.enabled", false)
+	v.SetDefault("breadth.highLowBars", 20)
 
 	// If config file is specified, load it and prioritize it
 	if configFile != "" {
@@ -81,6 +255,10 @@ func Load(configFile string) *Config {
 		os.Exit(1)
 	}
 
+	if len(cfg.Strategies) == 0 {
+		cfg.Strategies = DefaultStrategies()
+	}
+
 	return &cfg
 }
 
@@ -1,34 +1,107 @@
 package console
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/letieu/trade-bot/internal/scoring"
 	"github.com/letieu/trade-bot/internal/types"
 )
 
 type Bot struct {
+	// MaxSignals caps the output to the highest scoring signals; 0 prints all
+	MaxSignals int
 }
 
 func NewBot() *Bot {
 	return &Bot{}
 }
 
-func (b *Bot) SendSignals(signals []types.Signal) error {
+func (b *Bot) SendSignals(ctx context.Context, signals []types.Signal) error {
 	if len(signals) == 0 {
 		return nil
 	}
 
+	signals = append([]types.Signal(nil), signals...)
+	scoring.Rank(signals)
+	hidden := 0
+	if b.MaxSignals > 0 && len(signals) > b.MaxSignals {
+		hidden = len(signals) - b.MaxSignals
+		signals = signals[:b.MaxSignals]
+	}
+
 	message := b.formatSignalsMessage(signals)
+	if hidden > 0 {
+		message += fmt.Sprintf("+%d more with lower scores\n", hidden)
+	}
 	fmt.Println(message)
 	return nil
 }
 
-func (b *Bot) SendMessage(message string) error {
+func (b *Bot) SendMessage(ctx context.Context, message string) error {
 	fmt.Println(message)
 	return nil
 }
 
+func (b *Bot) SendMovers(ctx context.Context, report types.MoverReport) error {
+	fmt.Println(b.formatMovers(report))
+	return nil
+}
+
+func (b *Bot) formatMovers(report types.MoverReport) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("\n=== \033[1mRelative strength — %s\033[0m ===\n", report.Interval))
+	builder.WriteString(fmt.Sprintf("Returns over %v candles, %d symbols\n", report.Lookbacks, report.Symbols))
+	if report.BenchmarkReturns != nil {
+		builder.WriteString(fmt.Sprintf("%s %s\n", report.Benchmark, formatReturns(report.BenchmarkReturns)))
+	}
+
+	for _, m := range report.Top {
+		builder.WriteString(fmt.Sprintf("\033[92m[%s]\033[0m ⬆️ %s %.0f\n", m.Symbol, formatReturns(m.Returns), m.Score))
+	}
+	for _, m := range report.Bottom {
+		builder.WriteString(fmt.Sprintf("\033[91m[%s]\033[0m ⬇️ %s %.0f\n", m.Symbol, formatReturns(m.Returns), m.Score))
+	}
+	builder.WriteString("==========================\n")
+
+	return builder.String()
+}
+
+func (b *Bot) SendBreadth(ctx context.Context, breadth types.Breadth) error {
+	fmt.Println(b.formatBreadth(breadth))
+	return nil
+}
+
+func (b *Bot) formatBreadth(breadth types.Breadth) string {
+	var builder strings.Builder
+
+	adRatio := "n/a"
+	if ratio, ok := breadth.AdvanceDecline(); ok {
+		adRatio = fmt.Sprintf("%.2f", ratio)
+	}
+
+	builder.WriteString(fmt.Sprintf("\n=== \033[1mMarket breadth — %s\033[0m ===\n", breadth.Interval))
+	builder.WriteString(fmt.Sprintf("%d symbols\n", breadth.Symbols))
+	builder.WriteString(fmt.Sprintf("Green candles: %.0f%%\n", breadth.GreenPct))
+	builder.WriteString(fmt.Sprintf("Above EMA50: %.0f%%  EMA200: %.0f%%\n", breadth.AboveEMA50, breadth.AboveEMA200))
+	builder.WriteString(fmt.Sprintf("New %d-bar highs: \033[92m%d\033[0m  lows: \033[91m%d\033[0m\n", breadth.HighLowBars, breadth.NewHighs, breadth.NewLows))
+	builder.WriteString(fmt.Sprintf("Advancing %d / declining %d (A/D %s)\n", breadth.Advancing, breadth.Declining, adRatio))
+	builder.WriteString("==========================\n")
+
+	return builder.String()
+}
+
+// formatReturns lists percent returns, e.g. "+2.1% -0.4%"
+func formatReturns(returns []float64) string {
+	parts := make([]string, len(returns))
+	for i, r := range returns {
+		parts[i] = fmt.Sprintf("%+.1f%%", r)
+	}
+	return strings.Join(parts, " ")
+}
+
 func (b *Bot) formatSignalsMessage(signals []types.Signal) string {
 	if len(signals) == 0 {
 		return "No trading signals found"
@@ -44,13 +117,30 @@ func (b *Bot) formatSignalsMessage(signals []types.Signal) string {
 	for _, signal := range signals {
 		trendIcon := "⬆️"
 		colorCode := "\033[92m" // Green
-		if signal.Trend == "bearish" {
+		switch signal.Trend {
+		case "bearish":
 			trendIcon = "⬇️"
 			colorCode = "\033[91m" // Red
+		case "neutral":
+			trendIcon = "↔️"
+			colorCode = "\033[93m" // Yellow
 		}
 
 		line := fmt.Sprintf("%s[%s] %s\033[0m",
 			colorCode, signal.Symbol, trendIcon)
+		if signal.VolumeMultiple > 0 {
+			line += fmt.Sprintf(" VOL x%.1f", signal.VolumeMultiple)
+		}
+		if signal.Score.Total > 0 {
+			line += fmt.Sprintf(" %.0f", signal.Score.Total)
+		}
+		if c := signal.Confluence; c != nil {
+			agrees := "agrees"
+			if !c.Agrees {
+				agrees = "disagrees"
+			}
+			line += fmt.Sprintf(" (%s %s)", c.Interval, agrees)
+		}
 
 		builder.WriteString(line)
 		builder.WriteString("\n")
@@ -1,6 +1,7 @@
 package telegram
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strconv"
@@ -9,12 +10,17 @@ import (
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/scoring"
 	"github.com/letieu/trade-bot/internal/types"
 )
 
 type Bot struct {
 	config *config.TelegramConfig
 	bot    *tgbotapi.BotAPI
+
+	// MaxSignals caps each pattern and interval message to its highest scoring
+	// signals; 0 sends all of them
+	MaxSignals int
 }
 
 func NewBot(cfg *config.TelegramConfig) (*Bot, error) {
@@ -31,7 +37,7 @@ func NewBot(cfg *config.TelegramConfig) (*Bot, error) {
 	}, nil
 }
 
-func (b *Bot) SendSignals(signals []types.Signal) error {
+func (b *Bot) SendSignals(ctx context.Context, signals []types.Signal) error {
 	if len(signals) == 0 {
 		return nil
 	}
@@ -69,7 +75,7 @@ func (b *Bot) SendSignals(signals []types.Signal) error {
 
 	// Send messages for each group (potentially chunked)
 	for _, group := range sortedGroups {
-		if err := b.sendGroupedSignals(group.key.pattern, group.key.interval, group.signals); err != nil {
+		if err := b.sendGroupedSignals(ctx, group.key.pattern, group.key.interval, group.signals); err != nil {
 			return err
 		}
 	}
@@ -78,74 +84,96 @@ func (b *Bot) SendSignals(signals []types.Signal) error {
 }
 
 type symbolInfo struct {
-	symbol string
-	count  int
+	symbol     string
+	count      int
+	volume     float64 // volume multiple of a spike, 0 otherwise
+	score      float64
+	confluence string // marker for the higher timeframe check, if any
+}
+
+// trendSection is one line of symbols sharing a trend within a grouped message
+type trendSection struct {
+	icon    string
+	symbols []symbolInfo
 }
 
-func (b *Bot) sendGroupedSignals(pattern, interval string, signals []types.Signal) error {
-	// Create a map to store symbol with its consecutive count
-	var bullish []symbolInfo
-	var bearish []symbolInfo
+func (b *Bot) sendGroupedSignals(ctx context.Context, pattern, interval string, signals []types.Signal) error {
+	// Keep the highest scoring signals when capped
+	signals = append([]types.Signal(nil), signals...)
+	scoring.Rank(signals)
+	hidden := 0
+	if b.MaxSignals > 0 && len(signals) > b.MaxSignals {
+		hidden = len(signals) - b.MaxSignals
+		signals = signals[:b.MaxSignals]
+	}
+
+	// Split symbols by trend, keeping each one's consecutive count and score
+	bullish := trendSection{icon: "🟢"}
+	bearish := trendSection{icon: "🔴"}
+	neutral := trendSection{icon: "⚪"}
 
 	for _, signal := range signals {
 		info := symbolInfo{
 			symbol: signal.Symbol,
 			count:  signal.ConsecutiveCount,
+			volume: signal.VolumeMultiple,
+			score:  signal.Score.Total,
 		}
-		if signal.Trend == "bearish" {
-			bearish = append(bearish, info)
-		} else {
-			bullish = append(bullish, info)
+		if c := signal.Confluence; c != nil {
+			info.confluence = confluenceMarker(c)
 		}
-	}
-
-	// Sort by count (descending), then alphabetically by symbol
-	sort.Slice(bullish, func(i, j int) bool {
-		if bullish[i].count != bullish[j].count {
-			return bullish[i].count > bullish[j].count
+		switch signal.Trend {
+		case "bearish":
+			bearish.symbols = append(bearish.symbols, info)
+		case "neutral":
+			neutral.symbols = append(neutral.symbols, info)
+		default:
+			bullish.symbols = append(bullish.symbols, info)
 		}
-		return bullish[i].symbol < bullish[j].symbol
-	})
-	sort.Slice(bearish, func(i, j int) bool {
-		if bearish[i].count != bearish[j].count {
-			return bearish[i].count > bearish[j].count
-		}
-		return bearish[i].symbol < bearish[j].symbol
-	})
+	}
+	sections := []trendSection{bullish, bearish, neutral}
+
+	// Sort by score and count (descending), then alphabetically by symbol
+	totalSymbols := 0
+	for _, section := range sections {
+		infos := section.symbols
+		sort.Slice(infos, func(i, j int) bool {
+			if infos[i].score != infos[j].score {
+				return infos[i].score > infos[j].score
+			}
+			if infos[i].count != infos[j].count {
+				return infos[i].count > infos[j].count
+			}
+			return infos[i].symbol < infos[j].symbol
+		})
+		totalSymbols += len(infos)
+	}
 
 	// Calculate chunks based on message length (Telegram limit is 4096 chars)
 	// Estimate: each symbol ~30 chars with tags and count, header ~100 chars
 	// Safe limit: ~100 symbols per message to avoid hitting 4096 limit
 	maxSymbolsPerChunk := 100
 
-	totalSymbols := len(bullish) + len(bearish)
 	if totalSymbols <= maxSymbolsPerChunk {
 		// Single message
-		message := b.formatGroupedMessage(pattern, interval, bullish, bearish, 1, 1, signals[0].Timestamp)
-		return b.SendMessage(message)
+		message := b.formatGroupedMessage(pattern, interval, sections, 1, 1, signals[0].Timestamp)
+		return b.SendMessage(ctx, message+formatHidden(hidden))
 	}
 
-	// Need to chunk - split bullish and bearish separately
-	bullishChunks := chunkSymbolInfos(bullish, maxSymbolsPerChunk)
-	bearishChunks := chunkSymbolInfos(bearish, maxSymbolsPerChunk)
-
-	totalChunks := len(bullishChunks) + len(bearishChunks)
-	currentChunk := 0
-
-	// Send bullish chunks
-	for _, chunk := range bullishChunks {
-		currentChunk++
-		message := b.formatGroupedMessage(pattern, interval, chunk, nil, currentChunk, totalChunks, signals[0].Timestamp)
-		if err := b.SendMessage(message); err != nil {
-			return err
+	// Need to chunk - split each trend separately
+	var chunks []trendSection
+	for _, section := range sections {
+		for _, chunk := range chunkSymbolInfos(section.symbols, maxSymbolsPerChunk) {
+			chunks = append(chunks, trendSection{icon: section.icon, symbols: chunk})
 		}
 	}
 
-	// Send bearish chunks
-	for _, chunk := range bearishChunks {
-		currentChunk++
-		message := b.formatGroupedMessage(pattern, interval, nil, chunk, currentChunk, totalChunks, signals[0].Timestamp)
-		if err := b.SendMessage(message); err != nil {
+	for i, chunk := range chunks {
+		message := b.formatGroupedMessage(pattern, interval, []trendSection{chunk}, i+1, len(chunks), signals[0].Timestamp)
+		if i == len(chunks)-1 {
+			message += formatHidden(hidden)
+		}
+		if err := b.SendMessage(ctx, message); err != nil {
 			return err
 		}
 	}
@@ -153,6 +181,107 @@ func (b *Bot) sendGroupedSignals(pattern, interval string, signals []types.Signa
 	return nil
 }
 
+// SendMovers sends a relative strength report as its own message
+func (b *Bot) SendMovers(ctx context.Context, report types.MoverReport) error {
+	return b.SendMessage(ctx, formatMovers(report))
+}
+
+func formatMovers(report types.MoverReport) string {
+	var builder strings.Builder
+	loc := time.FixedZone("UTC+7", 7*60*60)
+
+	lookbacks := make([]string, len(report.Lookbacks))
+	for i, l := range report.Lookbacks {
+		lookbacks[i] = strconv.Itoa(l)
+	}
+
+	builder.WriteString(fmt.Sprintf("📈 <b>SỨC MẠNH TƯƠNG ĐỐI</b> (%s)\n", report.Interval))
+	builder.WriteString(fmt.Sprintf("🕒 <code>%s</code>\n", report.Timestamp.In(loc).Format("2006-01-02 15:04:05")))
+	builder.WriteString(fmt.Sprintf("<i>Returns over %s candles, %d symbols</i>\n", strings.Join(lookbacks, "/"), report.Symbols))
+	if report.BenchmarkReturns != nil {
+		builder.WriteString(fmt.Sprintf("<code>%s</code> %s\n", report.Benchmark, formatReturns(report.BenchmarkReturns)))
+	}
+
+	sections := []struct {
+		title  string
+		movers []types.Mover
+	}{
+		{"🚀 Top", report.Top},
+		{"🔻 Bottom", report.Bottom},
+	}
+	for _, section := range sections {
+		if len(section.movers) == 0 {
+			continue
+		}
+		builder.WriteString("\n" + section.title + "\n")
+		for _, m := range section.movers {
+			builder.WriteString(fmt.Sprintf("<code>%s</code> %s <i>%.0f</i>", m.Symbol, formatReturns(m.Returns), m.Score))
+			if len(m.Excess) > 0 {
+				// Against the benchmark over the longest lookback
+				builder.WriteString(fmt.Sprintf(" vs %s %+.1f%%", report.Benchmark, m.Excess[len(m.Excess)-1]))
+			}
+			builder.WriteString("\n")
+		}
+	}
+
+	return builder.String()
+}
+
+// SendBreadth sends a market breadth summary as its own message
+func (b *Bot) SendBreadth(ctx context.Context, breadth types.Breadth) error {
+	return b.SendMessage(ctx, formatBreadth(breadth))
+}
+
+func formatBreadth(breadth types.Breadth) string {
+	var builder strings.Builder
+	loc := time.FixedZone("UTC+7", 7*60*60)
+
+	builder.WriteString(fmt.Sprintf("🌐 <b>TOÀN CẢNH THỊ TRƯỜNG</b> (%s)\n", breadth.Interval))
+	builder.WriteString(fmt.Sprintf("🕒 <code>%s</code>\n", breadth.Timestamp.In(loc).Format("2006-01-02 15:04:05")))
+	builder.WriteString(fmt.Sprintf("<i>%d symbols</i>\n\n", breadth.Symbols))
+
+	builder.WriteString(fmt.Sprintf("🟢 Green candles: <b>%.0f%%</b>\n", breadth.GreenPct))
+	builder.WriteString(fmt.Sprintf("📏 Above EMA50: <b>%.0f%%</b>  EMA200: <b>%.0f%%</b>\n", breadth.AboveEMA50, breadth.AboveEMA200))
+	builder.WriteString(fmt.Sprintf("🏔 New %d-bar highs: <b>%d</b>  lows: <b>%d</b>\n", breadth.HighLowBars, breadth.NewHighs, breadth.NewLows))
+	builder.WriteString(fmt.Sprintf("⚖️ Advancing <b>%d</b> / declining <b>%d</b> (A/D %s)\n", breadth.Advancing, breadth.Declining, formatAdvanceDecline(breadth)))
+
+	return builder.String()
+}
+
+// formatAdvanceDecline shows the advance/decline ratio, or n/a without decliners
+func formatAdvanceDecline(breadth types.Breadth) string {
+	ratio, ok := breadth.AdvanceDecline()
+	if !ok {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.2f", ratio)
+}
+
+// formatReturns lists percent returns, e.g. "+2.1% -0.4%"
+func formatReturns(returns []float64) string {
+	parts := make([]string, len(returns))
+	for i, r := range returns {
+		parts[i] = fmt.Sprintf("%+.1f%%", r)
+	}
+	return strings.Join(parts, " ")
+}
+
+// confluenceMarker shows whether the higher timeframe agrees, e.g. "✅4h"
+func confluenceMarker(c *types.Confluence) string {
+	if c.Agrees {
+		return " ✅" + c.Interval
+	}
+	return " ⚠️" + c.Interval
+}
+
+// formatHidden notes how many lower scoring signals were left out
+func formatHidden(hidden int) string {
+	if hidden == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n<i>+%d more with lower scores</i>\n", hidden)
+}
+
 func chunkSymbolInfos(infos []symbolInfo, chunkSize int) [][]symbolInfo {
 	if len(infos) == 0 {
 		return nil
@@ -169,7 +298,13 @@ func chunkSymbolInfos(infos []symbolInfo, chunkSize int) [][]symbolInfo {
 	return chunks
 }
 
-func (b *Bot) SendMessage(message string) error {
+// SendMessage sends one message unless ctx is already done. The Telegram
+// client cannot abort a request in flight, so ctx is only checked up front.
+func (b *Bot) SendMessage(ctx context.Context, message string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to send telegram message: %w", err)
+	}
+
 	chatID, err := strconv.ParseInt(b.config.ChatID, 10, 64)
 	if err != nil {
 		return fmt.Errorf("invalid chat ID format: %w", err)
@@ -189,7 +324,7 @@ func (b *Bot) SendMessage(message string) error {
 	return nil
 }
 
-func (b *Bot) formatGroupedMessage(pattern, interval string, bullish, bearish []symbolInfo, currentChunk, totalChunks int, timestamp time.Time) string {
+func (b *Bot) formatGroupedMessage(pattern, interval string, sections []trendSection, currentChunk, totalChunks int, timestamp time.Time) string {
 	var builder strings.Builder
 	loc := time.FixedZone("UTC+7", 7*60*60)
 
@@ -207,33 +342,21 @@ func (b *Bot) formatGroupedMessage(pattern, interval string, bullish, bearish []
 		builder.WriteString(fmt.Sprintf("[%d/%d]\n\n", currentChunk, totalChunks))
 	}
 
-	// Format bullish symbols - on same line with spaces
-	if len(bullish) > 0 {
-		builder.WriteString("🟢 ")
-		for i, info := range bullish {
-			if i > 0 {
-				builder.WriteString("  ")
-			}
-			if info.count > 0 {
-				// Show count for consecutive candles pattern
-				builder.WriteString(fmt.Sprintf("<code>%s</code>(%d)", info.symbol, info.count))
-			} else {
-				// No count for other patterns
-				builder.WriteString(fmt.Sprintf("<code>%s</code>", info.symbol))
-			}
+	written := 0
+	for _, section := range sections {
+		if len(section.symbols) == 0 {
+			continue
 		}
-		builder.WriteString("\n")
-	}
 
-	// Add spacing between bullish and bearish
-	if len(bullish) > 0 && len(bearish) > 0 {
-		builder.WriteString("\n")
-	}
+		// Add spacing between trends
+		if written > 0 {
+			builder.WriteString("\n")
+		}
+		written++
 
-	// Format bearish symbols - on same line with spaces
-	if len(bearish) > 0 {
-		builder.WriteString("🔴 ")
-		for i, info := range bearish {
+		// Format symbols - on same line with spaces
+		builder.WriteString(section.icon + " ")
+		for i, info := range section.symbols {
 			if i > 0 {
 				builder.WriteString("  ")
 			}
@@ -244,6 +367,13 @@ func (b *Bot) formatGroupedMessage(pattern, interval string, bullish, bearish []
 				// No count for other patterns
 				builder.WriteString(fmt.Sprintf("<code>%s</code>", info.symbol))
 			}
+			if info.volume > 0 {
+				builder.WriteString(fmt.Sprintf(" VOL x%.1f", info.volume))
+			}
+			if info.score > 0 {
+				builder.WriteString(fmt.Sprintf(" <i>%.0f</i>", info.score))
+			}
+			builder.WriteString(info.confluence)
 		}
 		builder.WriteString("\n")
 	}
@@ -0,0 +1,64 @@
+// Package indicators implements technical indicators over candles.
+//
+// Every indicator comes in two forms: a streaming type that is fed one closed
+// candle at a time through Update, and an XSeries function that runs the
+// streaming type over a slice and returns one value per candle. Values before
+// an indicator has enough history are 0; use Ready to tell them apart.
+package indicators
+
+import "github.com/letieu/trade-bot/internal/types"
+
+// Indicator is a streaming indicator fed one closed candle at a time
+type Indicator interface {
+	Update(c types.Candle)
+	Ready() bool
+}
+
+// series feeds every candle to ind and records value after each update,
+// or 0 while the indicator is still warming up
+func series(candles []types.Candle, ind Indicator, value func() float64) []float64 {
+	out := make([]float64, len(candles))
+	for i, c := range candles {
+		ind.Update(c)
+		if ind.Ready() {
+			out[i] = value()
+		}
+	}
+	return out
+}
+
+// window is a fixed-size ring buffer of the most recent values
+type window struct {
+	values []float64
+	next   int
+	full   bool
+}
+
+func newWindow(size int) *window {
+	if size < 1 {
+		size = 1
+	}
+	return &window{values: make([]float64, size)}
+}
+
+// push adds v and returns the value it evicted, if any
+func (w *window) push(v float64) (evicted float64, ok bool) {
+	evicted, ok = w.values[w.next], w.full
+	w.values[w.next] = v
+	w.next++
+	if w.next == len(w.values) {
+		w.next = 0
+		w.full = true
+	}
+	return evicted, ok
+}
+
+func (w *window) each(fn func(v float64)) {
+	n := w.next
+	if w.full {
+		n = len(w.values)
+	}
+	for i := 0; i < n; i++ {
+		fn(w.values[i])
+	}
+}
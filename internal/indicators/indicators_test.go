@@ -0,0 +1,107 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// referenceCandles are hourly candles whose first 15 closes are the StockCharts
+// RSI example. Expected values in these tests come from an independent
+// straightforward implementation of the textbook formulas.
+func referenceCandles() []types.Candle {
+	rows := [][5]float64{
+		{44.14, 44.49, 44.02, 44.34, 1000},
+		{44.34, 44.54, 43.93, 44.09, 1259},
+		{44.09, 44.4, 43.89, 44.15, 1111},
+		{44.15, 44.45, 43.49, 43.61, 1370},
+		{43.61, 44.48, 43.45, 44.33, 1222},
+		{44.33, 45.03, 44.13, 44.83, 1074},
+		{44.83, 45.35, 44.71, 45.1, 1333},
+		{45.1, 45.72, 44.94, 45.42, 1185},
+		{45.42, 45.99, 45.22, 45.84, 1037},
+		{45.84, 46.28, 45.72, 46.08, 1296},
+		{46.08, 46.33, 45.73, 45.89, 1148},
+		{45.89, 46.33, 45.69, 46.03, 1000},
+		{46.03, 46.18, 45.49, 45.61, 1259},
+		{45.61, 46.48, 45.45, 46.28, 1111},
+		{46.28, 46.53, 46.08, 46.28, 1370},
+		{46.28, 46.58, 45.88, 46, 1222},
+		{46, 46.18, 45.84, 46.03, 1074},
+		{46.03, 46.61, 45.83, 46.41, 1333},
+		{46.41, 46.66, 46.1, 46.22, 1185},
+		{46.22, 46.52, 45.48, 45.64, 1037},
+		{45.64, 46.36, 45.44, 46.21, 1296},
+		{46.21, 46.45, 46.09, 46.25, 1148},
+		{46.25, 46.5, 45.55, 45.71, 1000},
+		{45.71, 46.75, 45.51, 46.45, 1259},
+		{46.45, 46.6, 45.66, 45.78, 1111},
+		{45.78, 45.98, 45.19, 45.35, 1370},
+		{45.35, 45.6, 43.83, 44.03, 1222},
+		{44.03, 44.48, 43.91, 44.18, 1074},
+		{44.18, 44.37, 44.02, 44.22, 1333},
+		{44.22, 44.77, 44.02, 44.57, 1185},
+		{44.57, 44.82, 43.3, 43.42, 1037},
+		{43.42, 43.72, 42.5, 42.66, 1296},
+		{42.66, 43.28, 42.46, 43.13, 1148},
+		{43.13, 43.75, 43.01, 43.55, 1000},
+		{43.55, 44.23, 43.39, 43.98, 1259},
+		{43.98, 44.7, 43.78, 44.4, 1111},
+		{44.4, 45, 44.28, 44.85, 1370},
+		{44.85, 45.4, 44.69, 45.2, 1222},
+		{45.2, 45.45, 44.85, 45.05, 1074},
+		{45.05, 45.9, 44.93, 45.6, 1333},
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]types.Candle, len(rows))
+	for i, r := range rows {
+		candles[i] = types.Candle{
+			Timestamp: start.Add(time.Duration(i) * time.Hour).UnixMilli(),
+			Open:      r[0],
+			High:      r[1],
+			Low:       r[2],
+			Close:     r[3],
+			Volume:    r[4],
+		}
+	}
+	return candles
+}
+
+const tolerance = 1e-3
+
+// assertValues checks the series at the given indexes and that it is zero before warmup
+func assertValues(t *testing.T, name string, got []float64, warmup int, want map[int]float64) {
+	t.Helper()
+	for i := 0; i < warmup; i++ {
+		if got[i] != 0 {
+			t.Errorf("%s[%d] = %v during warmup, want 0", name, i, got[i])
+		}
+	}
+	for i, w := range want {
+		if math.Abs(got[i]-w) > tolerance {
+			t.Errorf("%s[%d] = %.4f, want %.4f", name, i, got[i], w)
+		}
+	}
+}
+
+func TestWindow(t *testing.T) {
+	w := newWindow(3)
+	for i, v := range []float64{1, 2, 3, 4} {
+		evicted, ok := w.push(v)
+		if wantOK := i >= 3; ok != wantOK {
+			t.Fatalf("push(%v) evicted ok = %v, want %v", v, ok, wantOK)
+		}
+		if ok && evicted != 1 {
+			t.Errorf("push(%v) evicted %v, want 1", v, evicted)
+		}
+	}
+
+	sum := 0.0
+	w.each(func(v float64) { sum += v })
+	if sum != 9 {
+		t.Errorf("window sum = %v, want 9", sum)
+	}
+}
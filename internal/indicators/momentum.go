@@ -0,0 +1,194 @@
+package indicators
+
+import "github.com/letieu/trade-bot/internal/types"
+
+// RSI is Wilder's relative strength index of closes
+type RSI struct {
+	Period int
+
+	prev     float64
+	count    int
+	avgGain  float64
+	avgLoss  float64
+	value    float64
+	hasValue bool
+}
+
+func NewRSI(period int) *RSI {
+	return &RSI{Period: period}
+}
+
+// Update adds the candle's close
+func (r *RSI) Update(c types.Candle) { r.Add(c.Close) }
+
+// Add adds a raw value and returns the current RSI
+func (r *RSI) Add(v float64) float64 {
+	r.count++
+	if r.count == 1 {
+		r.prev = v
+		return r.value
+	}
+
+	change := v - r.prev
+	r.prev = v
+	gain, loss := 0.0, 0.0
+	if change > 0 {
+		gain = change
+	} else {
+		loss = -change
+	}
+
+	// The first averages are plain means of Period changes, then Wilder smoothing
+	changes := r.count - 1
+	period := float64(r.Period)
+	switch {
+	case changes < r.Period:
+		r.avgGain += gain
+		r.avgLoss += loss
+		return r.value
+	case changes == r.Period:
+		r.avgGain = (r.avgGain + gain) / period
+		r.avgLoss = (r.avgLoss + loss) / period
+	default:
+		r.avgGain = (r.avgGain*(period-1) + gain) / period
+		r.avgLoss = (r.avgLoss*(period-1) + loss) / period
+	}
+
+	switch {
+	case r.avgLoss == 0 && r.avgGain == 0:
+		r.value = 50
+	case r.avgLoss == 0:
+		r.value = 100
+	default:
+		r.value = 100 - 100/(1+r.avgGain/r.avgLoss)
+	}
+	r.hasValue = true
+	return r.value
+}
+
+func (r *RSI) Ready() bool    { return r.hasValue }
+func (r *RSI) Value() float64 { return r.value }
+
+// MACDValue is one MACD reading
+type MACDValue struct {
+	MACD      float64
+	Signal    float64
+	Histogram float64
+}
+
+// MACD is the difference of a fast and slow EMA of closes with an EMA signal line
+type MACD struct {
+	fast, slow, signal *EMA
+	value              MACDValue
+}
+
+func NewMACD(fast, slow, signal int) *MACD {
+	return &MACD{fast: NewEMA(fast), slow: NewEMA(slow), signal: NewEMA(signal)}
+}
+
+func (m *MACD) Update(c types.Candle) {
+	m.fast.Add(c.Close)
+	m.slow.Add(c.Close)
+	if !m.fast.Ready() || !m.slow.Ready() {
+		return
+	}
+
+	m.value.MACD = m.fast.Value() - m.slow.Value()
+	m.signal.Add(m.value.MACD)
+	if m.signal.Ready() {
+		m.value.Signal = m.signal.Value()
+		m.value.Histogram = m.value.MACD - m.value.Signal
+	}
+}
+
+// Ready reports whether the signal line, and so the histogram, is available
+func (m *MACD) Ready() bool      { return m.signal.Ready() }
+func (m *MACD) Value() MACDValue { return m.value }
+
+// StochasticValue is one stochastic oscillator reading
+type StochasticValue struct {
+	K float64
+	D float64
+}
+
+// Stochastic is the fast stochastic oscillator: %K is where the close sits in
+// the high-low range of the last KPeriod candles and %D is the SMA of %K
+type Stochastic struct {
+	KPeriod int
+
+	highs, lows *window
+	d           *SMA
+	value       StochasticValue
+}
+
+func NewStochastic(kPeriod, dPeriod int) *Stochastic {
+	return &Stochastic{
+		KPeriod: kPeriod,
+		highs:   newWindow(kPeriod),
+		lows:    newWindow(kPeriod),
+		d:       NewSMA(dPeriod),
+	}
+}
+
+func (s *Stochastic) Update(c types.Candle) {
+	s.highs.push(c.High)
+	s.lows.push(c.Low)
+	if !s.highs.full {
+		return
+	}
+
+	highest, lowest := c.High, c.Low
+	s.highs.each(func(v float64) {
+		if v > highest {
+			highest = v
+		}
+	})
+	s.lows.each(func(v float64) {
+		if v < lowest {
+			lowest = v
+		}
+	})
+
+	// A flat range has no position within it; report the midpoint
+	s.value.K = 50
+	if highest > lowest {
+		s.value.K = 100 * (c.Close - lowest) / (highest - lowest)
+	}
+	s.value.D = s.d.Add(s.value.K)
+}
+
+// Ready reports whether %D is available
+func (s *Stochastic) Ready() bool            { return s.d.Ready() }
+func (s *Stochastic) Value() StochasticValue { return s.value }
+
+// RSISeries returns the RSI of closes for every candle
+func RSISeries(candles []types.Candle, period int) []float64 {
+	r := NewRSI(period)
+	return series(candles, r, r.Value)
+}
+
+// MACDSeries returns the MACD for every candle; values are zero until the signal line is ready
+func MACDSeries(candles []types.Candle, fast, slow, signal int) []MACDValue {
+	m := NewMACD(fast, slow, signal)
+	out := make([]MACDValue, len(candles))
+	for i, c := range candles {
+		m.Update(c)
+		if m.Ready() {
+			out[i] = m.Value()
+		}
+	}
+	return out
+}
+
+// StochasticSeries returns the stochastic oscillator for every candle
+func StochasticSeries(candles []types.Candle, kPeriod, dPeriod int) []StochasticValue {
+	s := NewStochastic(kPeriod, dPeriod)
+	out := make([]StochasticValue, len(candles))
+	for i, c := range candles {
+		s.Update(c)
+		if s.Ready() {
+			out[i] = s.Value()
+		}
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package indicators
+
+import (
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+func TestRSISeries(t *testing.T) {
+	got := RSISeries(referenceCandles(), 14)
+	// 70.46 is the StockCharts example without its intermediate rounding
+	assertValues(t, "RSI(14)", got, 14, map[int]float64{14: 70.4641, 15: 66.2496, 16: 66.4809, 25: 50.3868, 39: 57.8699})
+}
+
+func TestRSI_EdgeCases(t *testing.T) {
+	tests := []struct {
+		name   string
+		closes []float64
+		want   float64
+	}{
+		{name: "only gains", closes: []float64{1, 2, 3, 4}, want: 100},
+		{name: "only losses", closes: []float64{4, 3, 2, 1}, want: 0},
+		{name: "flat", closes: []float64{5, 5, 5, 5}, want: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rsi := NewRSI(3)
+			for _, c := range tt.closes {
+				rsi.Add(c)
+			}
+			if !rsi.Ready() || rsi.Value() != tt.want {
+				t.Errorf("RSI = %v (ready %v), want %v", rsi.Value(), rsi.Ready(), tt.want)
+			}
+		})
+	}
+}
+
+func TestMACDSeries(t *testing.T) {
+	got := MACDSeries(referenceCandles(), 12, 26, 9)
+
+	var line, signal, histogram []float64
+	for _, v := range got {
+		line = append(line, v.MACD)
+		signal = append(signal, v.Signal)
+		histogram = append(histogram, v.Histogram)
+	}
+	// The signal line needs 26 + 9 - 1 candles
+	assertValues(t, "MACD", line, 33, map[int]float64{33: -0.4981, 39: -0.072})
+	assertValues(t, "Signal", signal, 33, map[int]float64{33: -0.148, 39: -0.2111})
+	assertValues(t, "Histogram", histogram, 33, map[int]float64{33: -0.3501, 39: 0.1391})
+
+	// The MACD line itself is available as soon as the slow EMA is
+	m := NewMACD(12, 26, 9)
+	for _, c := range referenceCandles()[:26] {
+		m.Update(c)
+	}
+	if v := m.Value().MACD; v < 0.3067-tolerance || v > 0.3067+tolerance {
+		t.Errorf("MACD line after 26 candles = %.4f, want 0.3067", v)
+	}
+}
+
+func TestStochasticSeries(t *testing.T) {
+	got := StochasticSeries(referenceCandles(), 14, 3)
+
+	var k, d []float64
+	for _, v := range got {
+		k = append(k, v.K)
+		d = append(d, v.D)
+	}
+	assertValues(t, "%K", k, 15, map[int]float64{15: 81.4696, 39: 91.2791})
+	assertValues(t, "%D", d, 15, map[int]float64{15: 88.9174, 39: 77.0141})
+}
+
+func TestStochastic_FlatRange(t *testing.T) {
+	s := NewStochastic(3, 1)
+	for i := 0; i < 3; i++ {
+		s.Update(types.Candle{Open: 10, High: 10, Low: 10, Close: 10})
+	}
+	if got := s.Value().K; got != 50 {
+		t.Errorf("%%K over a flat range = %v, want 50", got)
+	}
+}
@@ -0,0 +1,80 @@
+package indicators
+
+import "github.com/letieu/trade-bot/internal/types"
+
+// SMA is the simple moving average of the last Period values
+type SMA struct {
+	Period int
+
+	window *window
+	sum    float64
+	value  float64
+}
+
+func NewSMA(period int) *SMA {
+	return &SMA{Period: period, window: newWindow(period)}
+}
+
+// Update adds the candle's close
+func (s *SMA) Update(c types.Candle) { s.Add(c.Close) }
+
+// Add adds a raw value and returns the current average
+func (s *SMA) Add(v float64) float64 {
+	s.sum += v
+	if old, ok := s.window.push(v); ok {
+		s.sum -= old
+	}
+	if s.Ready() {
+		s.value = s.sum / float64(s.Period)
+	}
+	return s.value
+}
+
+func (s *SMA) Ready() bool    { return s.window.full }
+func (s *SMA) Value() float64 { return s.value }
+
+// EMA is the exponential moving average seeded with the SMA of the first Period values
+type EMA struct {
+	Period int
+
+	alpha float64
+	count int
+	sum   float64
+	value float64
+}
+
+func NewEMA(period int) *EMA {
+	return &EMA{Period: period, alpha: 2 / float64(period+1)}
+}
+
+// Update adds the candle's close
+func (e *EMA) Update(c types.Candle) { e.Add(c.Close) }
+
+// Add adds a raw value and returns the current average
+func (e *EMA) Add(v float64) float64 {
+	e.count++
+	switch {
+	case e.count < e.Period:
+		e.sum += v
+	case e.count == e.Period:
+		e.value = (e.sum + v) / float64(e.Period)
+	default:
+		e.value += (v - e.value) * e.alpha
+	}
+	return e.value
+}
+
+func (e *EMA) Ready() bool    { return e.Period > 0 && e.count >= e.Period }
+func (e *EMA) Value() float64 { return e.value }
+
+// SMASeries returns the SMA of closes for every candle
+func SMASeries(candles []types.Candle, period int) []float64 {
+	s := NewSMA(period)
+	return series(candles, s, s.Value)
+}
+
+// EMASeries returns the EMA of closes for every candle
+func EMASeries(candles []types.Candle, period int) []float64 {
+	e := NewEMA(period)
+	return series(candles, e, e.Value)
+}
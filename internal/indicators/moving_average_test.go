@@ -0,0 +1,30 @@
+package indicators
+
+import "testing"
+
+func TestSMASeries(t *testing.T) {
+	got := SMASeries(referenceCandles(), 10)
+	assertValues(t, "SMA(10)", got, 9, map[int]float64{9: 44.779, 20: 46.071, 39: 44.184})
+}
+
+func TestEMASeries(t *testing.T) {
+	got := EMASeries(referenceCandles(), 10)
+	// Seeded with the SMA of the first 10 closes
+	assertValues(t, "EMA(10)", got, 9, map[int]float64{9: 44.779, 10: 44.981, 20: 45.9321, 39: 44.7138})
+}
+
+func TestEMA_Streaming(t *testing.T) {
+	candles := referenceCandles()
+	series := EMASeries(candles, 20)
+
+	ema := NewEMA(20)
+	for i, c := range candles {
+		ema.Update(c)
+		if ready := i >= 19; ema.Ready() != ready {
+			t.Fatalf("Ready() after %d candles = %v, want %v", i+1, ema.Ready(), ready)
+		}
+		if ema.Ready() && ema.Value() != series[i] {
+			t.Errorf("streaming EMA[%d] = %v, series = %v", i, ema.Value(), series[i])
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// ADXValue is one directional movement reading
+type ADXValue struct {
+	ADX     float64
+	PlusDI  float64
+	MinusDI float64
+}
+
+// ADX is Wilder's average directional index. The DI lines are available after
+// Period+1 candles and the ADX after 2*Period.
+type ADX struct {
+	Period int
+
+	tr                       trueRange
+	prev                     types.Candle
+	count                    int
+	trSum, plusSum, minusSum float64
+	dxCount                  int
+	value                    ADXValue
+}
+
+func NewADX(period int) *ADX {
+	return &ADX{Period: period}
+}
+
+func (a *ADX) Update(c types.Candle) {
+	tr, ok := a.tr.next(c)
+	prev := a.prev
+	a.prev = c
+	if !ok || a.Period <= 0 {
+		return
+	}
+
+	plusDM, minusDM := 0.0, 0.0
+	up, down := c.High-prev.High, prev.Low-c.Low
+	if up > down && up > 0 {
+		plusDM = up
+	}
+	if down > up && down > 0 {
+		minusDM = down
+	}
+
+	// Wilder keeps running sums: the first Period values, then sum - sum/Period + new
+	a.count++
+	period := float64(a.Period)
+	if a.count <= a.Period {
+		a.trSum += tr
+		a.plusSum += plusDM
+		a.minusSum += minusDM
+		if a.count < a.Period {
+			return
+		}
+	} else {
+		a.trSum += tr - a.trSum/period
+		a.plusSum += plusDM - a.plusSum/period
+		a.minusSum += minusDM - a.minusSum/period
+	}
+
+	if a.trSum > 0 {
+		a.value.PlusDI = 100 * a.plusSum / a.trSum
+		a.value.MinusDI = 100 * a.minusSum / a.trSum
+	}
+	dx := 0.0
+	if sum := a.value.PlusDI + a.value.MinusDI; sum > 0 {
+		dx = 100 * math.Abs(a.value.PlusDI-a.value.MinusDI) / sum
+	}
+
+	a.dxCount++
+	switch {
+	case a.dxCount < a.Period:
+		a.value.ADX += dx
+	case a.dxCount == a.Period:
+		a.value.ADX = (a.value.ADX + dx) / period
+	default:
+		a.value.ADX = (a.value.ADX*(period-1) + dx) / period
+	}
+}
+
+// Ready reports whether the ADX line is available
+func (a *ADX) Ready() bool { return a.Period > 0 && a.dxCount >= a.Period }
+
+// Value returns the current reading; while warming up ADX holds a partial sum
+func (a *ADX) Value() ADXValue { return a.value }
+
+// ADXSeries returns the ADX and DI lines for every candle
+func ADXSeries(candles []types.Candle, period int) []ADXValue {
+	a := NewADX(period)
+	out := make([]ADXValue, len(candles))
+	for i, c := range candles {
+		a.Update(c)
+		if a.Ready() {
+			out[i] = a.Value()
+		}
+	}
+	return out
+}
@@ -0,0 +1,30 @@
+package indicators
+
+import "testing"
+
+func TestADXSeries(t *testing.T) {
+	got := ADXSeries(referenceCandles(), 14)
+
+	var adx, plus, minus []float64
+	for _, v := range got {
+		adx = append(adx, v.ADX)
+		plus = append(plus, v.PlusDI)
+		minus = append(minus, v.MinusDI)
+	}
+	assertValues(t, "ADX", adx, 27, map[int]float64{27: 25.2089, 39: 22.3657})
+	assertValues(t, "+DI", plus, 27, map[int]float64{39: 23.967})
+	assertValues(t, "-DI", minus, 27, map[int]float64{39: 17.0949})
+
+	// The DI lines are available before the ADX
+	a := NewADX(14)
+	for _, c := range referenceCandles()[:15] {
+		a.Update(c)
+	}
+	if v := a.Value(); v.PlusDI < 21.6323-tolerance || v.PlusDI > 21.6323+tolerance ||
+		v.MinusDI < 7.9646-tolerance || v.MinusDI > 7.9646+tolerance {
+		t.Errorf("DI after 15 candles = %+v, want +DI 21.6323 -DI 7.9646", v)
+	}
+	if a.Ready() {
+		t.Error("ADX should not be ready after 15 candles")
+	}
+}
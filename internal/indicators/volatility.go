@@ -0,0 +1,120 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// trueRange tracks the previous close so a stream of candles yields true ranges
+type trueRange struct {
+	prevClose float64
+	hasPrev   bool
+}
+
+// next returns the candle's true range, or false for the first candle which
+// has no previous close
+func (t *trueRange) next(c types.Candle) (float64, bool) {
+	prev, ok := t.prevClose, t.hasPrev
+	t.prevClose, t.hasPrev = c.Close, true
+	if !ok {
+		return 0, false
+	}
+	return math.Max(c.High-c.Low, math.Max(math.Abs(c.High-prev), math.Abs(c.Low-prev))), true
+}
+
+// ATR is Wilder's average true range. The first value is the mean of the
+// first Period true ranges, so it is available from the Period+1th candle.
+type ATR struct {
+	Period int
+
+	tr    trueRange
+	count int
+	value float64
+}
+
+func NewATR(period int) *ATR {
+	return &ATR{Period: period}
+}
+
+func (a *ATR) Update(c types.Candle) {
+	tr, ok := a.tr.next(c)
+	if !ok || a.Period <= 0 {
+		return
+	}
+
+	a.count++
+	period := float64(a.Period)
+	switch {
+	case a.count < a.Period:
+		a.value += tr
+	case a.count == a.Period:
+		a.value = (a.value + tr) / period
+	default:
+		a.value = (a.value*(period-1) + tr) / period
+	}
+}
+
+func (a *ATR) Ready() bool    { return a.Period > 0 && a.count >= a.Period }
+func (a *ATR) Value() float64 { return a.value }
+
+// BollingerValue is one Bollinger Bands reading
+type BollingerValue struct {
+	Middle float64
+	Upper  float64
+	Lower  float64
+}
+
+// Bollinger bands sit K population standard deviations around the SMA of closes
+type Bollinger struct {
+	Period int
+	K      float64
+
+	sma   *SMA
+	value BollingerValue
+}
+
+func NewBollinger(period int, k float64) *Bollinger {
+	return &Bollinger{Period: period, K: k, sma: NewSMA(period)}
+}
+
+func (b *Bollinger) Update(c types.Candle) {
+	mean := b.sma.Add(c.Close)
+	if !b.sma.Ready() {
+		return
+	}
+
+	variance := 0.0
+	b.sma.window.each(func(v float64) {
+		variance += (v - mean) * (v - mean)
+	})
+	deviation := math.Sqrt(variance / float64(b.Period))
+
+	b.value = BollingerValue{
+		Middle: mean,
+		Upper:  mean + b.K*deviation,
+		Lower:  mean - b.K*deviation,
+	}
+}
+
+func (b *Bollinger) Ready() bool           { return b.sma.Ready() }
+func (b *Bollinger) Value() BollingerValue { return b.value }
+
+// ATRSeries returns the ATR for every candle
+func ATRSeries(candles []types.Candle, period int) []float64 {
+	a := NewATR(period)
+	return series(candles, a, a.Value)
+}
+
+// BollingerSeries returns the Bollinger Bands for every candle
+func BollingerSeries(candles []types.Candle, period int, k float64) []BollingerValue {
+	b := NewBollinger(period, k)
+	out := make([]BollingerValue, len(candles))
+	for i, c := range candles {
+		b.Update(c)
+		if b.Ready() {
+			out[i] = b.Value()
+		}
+	}
+	return out
+}
@@ -0,0 +1,22 @@
+package indicators
+
+import "testing"
+
+func TestATRSeries(t *testing.T) {
+	got := ATRSeries(referenceCandles(), 14)
+	assertValues(t, "ATR(14)", got, 14, map[int]float64{14: 0.7264, 15: 0.7245, 39: 0.8327})
+}
+
+func TestBollingerSeries(t *testing.T) {
+	got := BollingerSeries(referenceCandles(), 20, 2)
+
+	var middle, upper, lower []float64
+	for _, v := range got {
+		middle = append(middle, v.Middle)
+		upper = append(upper, v.Upper)
+		lower = append(lower, v.Lower)
+	}
+	assertValues(t, "Middle", middle, 19, map[int]float64{19: 45.409, 39: 44.7295})
+	assertValues(t, "Upper", upper, 19, map[int]float64{19: 47.1153, 39: 46.8604})
+	assertValues(t, "Lower", lower, 19, map[int]float64{19: 43.7027, 39: 42.5986})
+}
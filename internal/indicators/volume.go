@@ -0,0 +1,86 @@
+package indicators
+
+import (
+	"time"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// OBV is on-balance volume: volume added on up closes and subtracted on down closes
+type OBV struct {
+	prevClose float64
+	count     int
+	value     float64
+}
+
+func NewOBV() *OBV {
+	return &OBV{}
+}
+
+func (o *OBV) Update(c types.Candle) {
+	if o.count > 0 {
+		switch {
+		case c.Close > o.prevClose:
+			o.value += c.Volume
+		case c.Close < o.prevClose:
+			o.value -= c.Volume
+		}
+	}
+	o.prevClose = c.Close
+	o.count++
+}
+
+func (o *OBV) Ready() bool    { return o.count > 0 }
+func (o *OBV) Value() float64 { return o.value }
+
+// VWAP is the volume-weighted average of typical price, (high+low+close)/3.
+// With a non-zero Anchor it restarts whenever a candle opens in a new anchor
+// period, e.g. 24h for a daily VWAP; otherwise it covers every candle seen.
+type VWAP struct {
+	Anchor time.Duration
+
+	period      int64
+	priceVolume float64
+	volume      float64
+	value       float64
+	count       int
+}
+
+func NewVWAP(anchor time.Duration) *VWAP {
+	return &VWAP{Anchor: anchor}
+}
+
+func (v *VWAP) Update(c types.Candle) {
+	if v.Anchor > 0 {
+		period := c.Timestamp / v.Anchor.Milliseconds()
+		if v.count > 0 && period != v.period {
+			v.priceVolume, v.volume = 0, 0
+		}
+		v.period = period
+	}
+
+	typical := (c.High + c.Low + c.Close) / 3
+	v.priceVolume += typical * c.Volume
+	v.volume += c.Volume
+	v.count++
+
+	v.value = typical
+	if v.volume > 0 {
+		v.value = v.priceVolume / v.volume
+	}
+}
+
+func (v *VWAP) Ready() bool    { return v.count > 0 }
+func (v *VWAP) Value() float64 { return v.value }
+
+// OBVSeries returns on-balance volume for every candle
+func OBVSeries(candles []types.Candle) []float64 {
+	o := NewOBV()
+	return series(candles, o, o.Value)
+}
+
+// VWAPSeries returns the VWAP for every candle
+func VWAPSeries(candles []types.Candle, anchor time.Duration) []float64 {
+	v := NewVWAP(anchor)
+	return series(candles, v, v.Value)
+}
@@ -0,0 +1,35 @@
+package indicators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+func TestOBVSeries(t *testing.T) {
+	got := OBVSeries(referenceCandles())
+	assertValues(t, "OBV", got, 0, map[int]float64{0: 0, 1: -1259, 2: -148, 39: 11924})
+}
+
+func TestVWAPSeries(t *testing.T) {
+	got := VWAPSeries(referenceCandles(), 0)
+	assertValues(t, "VWAP", got, 0, map[int]float64{0: 44.2833, 39: 45.0826})
+}
+
+func TestVWAP_AnchorResets(t *testing.T) {
+	day := time.Date(2024, 1, 1, 22, 0, 0, 0, time.UTC)
+	candles := []types.Candle{
+		{Timestamp: day.UnixMilli(), High: 10, Low: 10, Close: 10, Volume: 1},
+		{Timestamp: day.Add(time.Hour).UnixMilli(), High: 20, Low: 20, Close: 20, Volume: 1},
+		{Timestamp: day.Add(2 * time.Hour).UnixMilli(), High: 30, Low: 30, Close: 30, Volume: 1},
+	}
+
+	got := VWAPSeries(candles, 24*time.Hour)
+	want := []float64{10, 15, 30} // the third candle opens a new UTC day
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("VWAP[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
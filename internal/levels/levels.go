@@ -0,0 +1,161 @@
+// Package levels derives horizontal support and resistance zones from candle
+// history. Two kinds of evidence mark a price as a level:
+//   - swing pivots: highs and lows beyond the candles on either side of them
+//   - volume clustering: prices where unusually much volume closed, the high
+//     volume nodes of a volume profile
+//
+// Nearby prices are merged into zones, and a zone is kept when it was pivoted
+// at often enough or holds a high volume node.
+package levels
+
+import (
+	"math"
+	"sort"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// Config tunes zone detection
+type Config struct {
+	PivotBars    int     // Candles on each side a swing high or low must exceed
+	Width        float64 // Minimum zone width in price; prices closer than this merge
+	MinTouches   int     // Pivots needed for a zone without a high volume node
+	Bins         int     // Price bins in the volume profile, 0 disables volume clustering
+	VolumeFactor float64 // Bin volume against the average bin marking a high volume node
+}
+
+// Zone is a horizontal price band
+type Zone struct {
+	Low        float64 `json:"low"`
+	High       float64 `json:"high"`
+	Touches    int     `json:"touches"`     // swing pivots inside the zone
+	HighVolume bool    `json:"high_volume"` // holds a high volume node
+	Volume     float64 `json:"volume"`      // volume of candles closing inside the zone
+}
+
+// Contains reports whether price is inside the zone
+func (z Zone) Contains(price float64) bool {
+	return price >= z.Low && price <= z.High
+}
+
+// Mid is the centre of the zone
+func (z Zone) Mid() float64 {
+	return (z.Low + z.High) / 2
+}
+
+// point is one piece of evidence for a level
+type point struct {
+	price float64
+	pivot bool // a swing pivot rather than a volume node
+}
+
+// Find returns the zones of candles ordered from lowest to highest
+func Find(candles []types.Candle, cfg Config) []Zone {
+	if len(candles) == 0 {
+		return nil
+	}
+
+	points := append(pivots(candles, cfg.PivotBars), volumeNodes(candles, cfg.Bins, cfg.VolumeFactor)...)
+	sort.Slice(points, func(i, j int) bool { return points[i].price < points[j].price })
+
+	var zones []Zone
+	for i := 0; i < len(points); {
+		// A cluster spans at most Width from its lowest point, so zones cannot drift
+		j := i
+		zone := Zone{Low: points[i].price, High: points[i].price}
+		for ; j < len(points) && points[j].price-points[i].price <= cfg.Width; j++ {
+			zone.High = points[j].price
+			if points[j].pivot {
+				zone.Touches++
+			} else {
+				zone.HighVolume = true
+			}
+		}
+		i = j
+
+		if zone.Touches < cfg.MinTouches && !zone.HighVolume {
+			continue
+		}
+		if pad := (cfg.Width - (zone.High - zone.Low)) / 2; pad > 0 {
+			zone.Low -= pad
+			zone.High += pad
+		}
+		for _, c := range candles {
+			if zone.Contains(c.Close) {
+				zone.Volume += c.Volume
+			}
+		}
+		zones = append(zones, zone)
+	}
+
+	return zones
+}
+
+// pivots returns the swing highs and lows that have bars candles on each side
+func pivots(candles []types.Candle, bars int) []point {
+	if bars < 1 {
+		return nil
+	}
+
+	var points []point
+	for i := bars; i < len(candles)-bars; i++ {
+		high, low := true, true
+		for j := i - bars; j <= i+bars; j++ {
+			if j == i {
+				continue
+			}
+			high = high && candles[i].High > candles[j].High
+			low = low && candles[i].Low < candles[j].Low
+		}
+		if high {
+			points = append(points, point{price: candles[i].High, pivot: true})
+		}
+		if low {
+			points = append(points, point{price: candles[i].Low, pivot: true})
+		}
+	}
+	return points
+}
+
+// volumeNodes builds a volume profile of closes over bins and returns the
+// centres of bins that peak above factor times the average bin
+func volumeNodes(candles []types.Candle, bins int, factor float64) []point {
+	if bins < 1 {
+		return nil
+	}
+
+	low, high := candles[0].Low, candles[0].High
+	for _, c := range candles[1:] {
+		low = math.Min(low, c.Low)
+		high = math.Max(high, c.High)
+	}
+	if high <= low {
+		return nil
+	}
+	size := (high - low) / float64(bins)
+
+	profile := make([]float64, bins)
+	total := 0.0
+	for _, c := range candles {
+		bin := int((c.Close - low) / size)
+		if bin >= bins {
+			bin = bins - 1
+		}
+		profile[bin] += c.Volume
+		total += c.Volume
+	}
+	threshold := factor * total / float64(bins)
+
+	var points []point
+	for i, volume := range profile {
+		if volume <= threshold || total == 0 {
+			continue
+		}
+		// Local peaks only, so one wide cluster gives one node
+		if (i > 0 && profile[i-1] > volume) || (i < bins-1 && profile[i+1] >= volume) {
+			continue
+		}
+		points = append(points, point{price: low + (float64(i)+0.5)*size})
+	}
+	return points
+}
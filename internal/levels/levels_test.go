@@ -0,0 +1,72 @@
+package levels
+
+import (
+	"math"
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+func candle(price, volume float64) types.Candle {
+	return types.Candle{Open: price, High: price + 0.5, Low: price - 0.5, Close: price, Volume: volume}
+}
+
+func series(prices ...float64) []types.Candle {
+	candles := make([]types.Candle, len(prices))
+	for i, p := range prices {
+		candles[i] = candle(p, 1)
+	}
+	return candles
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestFind_Pivots(t *testing.T) {
+	// Lows twice at 100, highs at 110 and 110.3, one lone high at 120
+	candles := series(105, 110, 105, 100, 105, 110.3, 105, 100, 105, 120, 105)
+
+	zones := Find(candles, Config{PivotBars: 1, Width: 1, MinTouches: 2})
+	if len(zones) != 2 {
+		t.Fatalf("got %d zones, want 2: %+v", len(zones), zones)
+	}
+
+	support, resistance := zones[0], zones[1]
+	if !near(support.Low, 99) || !near(support.High, 100) || support.Touches != 2 {
+		t.Errorf("support = %+v, want 99-100 with 2 touches", support)
+	}
+	// 110.5 and 110.8 padded to the minimum width
+	if !near(resistance.Low, 110.15) || !near(resistance.High, 111.15) || resistance.Touches != 2 {
+		t.Errorf("resistance = %+v, want 110.15-111.15 with 2 touches", resistance)
+	}
+}
+
+func TestFind_VolumeNodes(t *testing.T) {
+	candles := series(105, 110, 105, 100, 105, 110.3, 105, 100, 105, 120, 105)
+	for i := range candles {
+		if candles[i].Close == 105 {
+			candles[i].Volume = 100
+		}
+	}
+
+	// Too few pivots anywhere, so only the volume node remains
+	zones := Find(candles, Config{PivotBars: 1, Width: 1, MinTouches: 3, Bins: 10, VolumeFactor: 2})
+	if len(zones) != 1 {
+		t.Fatalf("got %d zones, want 1: %+v", len(zones), zones)
+	}
+
+	zone := zones[0]
+	if !zone.HighVolume || zone.Touches != 0 {
+		t.Errorf("zone = %+v, want a high volume node without touches", zone)
+	}
+	if !zone.Contains(105) || zone.Volume != 600 {
+		t.Errorf("zone = %+v, want it around 105 with volume 600", zone)
+	}
+}
+
+func TestFind_Empty(t *testing.T) {
+	if zones := Find(nil, Config{PivotBars: 1, Width: 1, MinTouches: 1, Bins: 10, VolumeFactor: 2}); zones != nil {
+		t.Errorf("Find(nil) = %+v, want nil", zones)
+	}
+}
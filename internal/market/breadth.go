@@ -0,0 +1,98 @@
+package market
+
+import (
+	"github.com/letieu/trade-bot/internal/indicators"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// BreadthHistory is the candles Breadth needs for EMA(200) to be ready and
+// settle a little
+const BreadthHistory = 250
+
+// Breadth summarises the last closed candle of every symbol in series.
+// Symbols whose candles stop before the latest one are left out, and symbols
+// too short for an EMA or the new high/low window are left out of that figure
+// only. It returns nil for an empty series.
+func Breadth(interval string, series map[string][]types.Candle, highLowBars int) *types.Breadth {
+	b := &types.Breadth{Interval: interval, HighLowBars: highLowBars}
+	series = current(series)
+
+	green, ema50, ema200 := 0, counter{}, counter{}
+	for _, candles := range series {
+		if len(candles) == 0 {
+			continue
+		}
+		b.Symbols++
+		last := candles[len(candles)-1]
+
+		if last.Close > last.Open {
+			green++
+		}
+		ema50.add(aboveEMA(candles, 50))
+		ema200.add(aboveEMA(candles, 200))
+
+		if len(candles) >= 2 {
+			switch prev := candles[len(candles)-2]; {
+			case last.Close > prev.Close:
+				b.Advancing++
+			case last.Close < prev.Close:
+				b.Declining++
+			}
+		}
+
+		if highLowBars > 0 && len(candles) > highLowBars {
+			window := candles[len(candles)-1-highLowBars : len(candles)-1]
+			high, low := window[0].High, window[0].Low
+			for _, c := range window[1:] {
+				high = max(high, c.High)
+				low = min(low, c.Low)
+			}
+			if last.High > high {
+				b.NewHighs++
+			}
+			if last.Low < low {
+				b.NewLows++
+			}
+		}
+	}
+	if b.Symbols == 0 {
+		return nil
+	}
+
+	b.GreenPct = float64(green) / float64(b.Symbols) * 100
+	b.AboveEMA50 = ema50.pct()
+	b.AboveEMA200 = ema200.pct()
+	return b
+}
+
+// aboveEMA reports whether the last close is above EMA(period); ok is false
+// while the EMA is not ready
+func aboveEMA(candles []types.Candle, period int) (above, ok bool) {
+	if len(candles) < period {
+		return false, false
+	}
+	ema := indicators.EMASeries(candles, period)
+	return candles[len(candles)-1].Close > ema[len(ema)-1], true
+}
+
+// counter is the share of eligible symbols meeting a condition
+type counter struct {
+	yes, total int
+}
+
+func (c *counter) add(yes, ok bool) {
+	if !ok {
+		return
+	}
+	c.total++
+	if yes {
+		c.yes++
+	}
+}
+
+func (c counter) pct() float64 {
+	if c.total == 0 {
+		return 0
+	}
+	return float64(c.yes) / float64(c.total) * 100
+}
@@ -0,0 +1,71 @@
+package market
+
+import (
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// trend is n candles, each opening at the previous close and moving by step
+func trend(n int, step float64) []types.Candle {
+	candles := make([]types.Candle, n)
+	for i := range candles {
+		open, close := 100+step*float64(i), 100+step*float64(i+1)
+		candles[i] = types.Candle{Open: open, High: max(open, close), Low: min(open, close), Close: close}
+	}
+	return candles
+}
+
+func TestBreadth(t *testing.T) {
+	series := map[string][]types.Candle{
+		"UPUSDT":   trend(60, 1),
+		"DOWNUSDT": trend(60, -1),
+		"FLATUSDT": trend(60, 0),
+		"NEWUSDT":  trend(10, 1), // too short for EMA(50) and the high/low window
+	}
+
+	b := Breadth("1h", series, 20)
+	if b == nil {
+		t.Fatal("Breadth() = nil")
+	}
+	if b.Interval != "1h" || b.Symbols != 4 || b.HighLowBars != 20 {
+		t.Errorf("breadth = %s with %d symbols over %d bars, want 1h with 4 over 20", b.Interval, b.Symbols, b.HighLowBars)
+	}
+	if b.GreenPct != 50 {
+		t.Errorf("green = %v%%, want 50%%", b.GreenPct)
+	}
+	if !near(b.AboveEMA50, 100.0/3) {
+		t.Errorf("above EMA50 = %v%%, want 1 of the 3 long enough", b.AboveEMA50)
+	}
+	if b.AboveEMA200 != 0 {
+		t.Errorf("above EMA200 = %v%%, want 0 with no series long enough", b.AboveEMA200)
+	}
+	if b.NewHighs != 1 || b.NewLows != 1 {
+		t.Errorf("new highs/lows = %d/%d, want 1/1", b.NewHighs, b.NewLows)
+	}
+	if b.Advancing != 2 || b.Declining != 1 {
+		t.Errorf("advancing/declining = %d/%d, want 2/1", b.Advancing, b.Declining)
+	}
+	if ratio, ok := b.AdvanceDecline(); !ok || ratio != 2 {
+		t.Errorf("AdvanceDecline() = %v, %v, want 2, true", ratio, ok)
+	}
+}
+
+func TestBreadth_DropsStaleSeries(t *testing.T) {
+	live, stale := trend(30, 1), trend(30, -1)
+	for i := range live {
+		live[i].Timestamp = int64(i + 10) // the stale series stopped ten candles earlier
+		stale[i].Timestamp = int64(i)
+	}
+
+	b := Breadth("1h", map[string][]types.Candle{"BTCUSDT": live, "DEADUSDT": stale}, 20)
+	if b == nil || b.Symbols != 1 || b.Declining != 0 {
+		t.Errorf("Breadth() = %+v, want only the live symbol", b)
+	}
+}
+
+func TestBreadth_Empty(t *testing.T) {
+	if b := Breadth("1h", map[string][]types.Candle{"BTCUSDT": nil}, 20); b != nil {
+		t.Errorf("Breadth() = %+v, want nil without candles", b)
+	}
+}
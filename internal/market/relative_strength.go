@@ -0,0 +1,127 @@
+// Package market compares symbols with each other across one scan of an
+// interval, rather than looking at each symbol alone.
+package market
+
+import (
+	"sort"
+
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// RelativeStrength ranks the symbols of series by their returns over each
+// lookback. A symbol's score is the average of its percentile on every
+// lookback, so one that leads on all of them scores 100. Symbols with fewer
+// than the longest lookback plus one candles, or whose candles stop before the
+// latest one in series, are left out, and nil is returned when fewer than two
+// remain.
+func RelativeStrength(interval string, series map[string][]types.Candle, cfg config.RelativeStrengthConfig) *types.MoverReport {
+	series = current(series)
+
+	var movers []types.Mover
+	for symbol, candles := range series {
+		if r := returns(candles, cfg.Lookbacks); r != nil {
+			movers = append(movers, types.Mover{Symbol: symbol, Returns: r})
+		}
+	}
+	if len(movers) < 2 {
+		return nil
+	}
+
+	for i := range cfg.Lookbacks {
+		for j, pct := range percentiles(movers, i) {
+			movers[j].Score += pct / float64(len(cfg.Lookbacks))
+		}
+	}
+
+	report := &types.MoverReport{
+		Interval:         interval,
+		Lookbacks:        cfg.Lookbacks,
+		Benchmark:        cfg.Benchmark,
+		BenchmarkReturns: returns(series[cfg.Benchmark], cfg.Lookbacks),
+		Symbols:          len(movers),
+	}
+	if report.BenchmarkReturns != nil {
+		for i := range movers {
+			movers[i].Excess = make([]float64, len(cfg.Lookbacks))
+			for j, r := range movers[i].Returns {
+				movers[i].Excess[j] = r - report.BenchmarkReturns[j]
+			}
+		}
+	}
+
+	sort.Slice(movers, func(i, j int) bool {
+		if movers[i].Score != movers[j].Score {
+			return movers[i].Score > movers[j].Score
+		}
+		return movers[i].Symbol < movers[j].Symbol
+	})
+
+	// Top and bottom never share a symbol
+	top := min(cfg.Top, len(movers))
+	report.Top = movers[:top]
+	for i := len(movers) - 1; i >= top && len(report.Bottom) < cfg.Top; i-- {
+		report.Bottom = append(report.Bottom, movers[i])
+	}
+	return report
+}
+
+// current drops the series that do not end on the latest candle of any of
+// them, such as a halted or delisted symbol whose data stopped days ago
+func current(series map[string][]types.Candle) map[string][]types.Candle {
+	var latest int64
+	for _, candles := range series {
+		if len(candles) > 0 {
+			latest = max(latest, candles[len(candles)-1].Timestamp)
+		}
+	}
+
+	out := make(map[string][]types.Candle, len(series))
+	for symbol, candles := range series {
+		if len(candles) > 0 && candles[len(candles)-1].Timestamp == latest {
+			out[symbol] = candles
+		}
+	}
+	return out
+}
+
+// returns is the percent change of the last close over each lookback, or nil
+// when candles are too short or a base close is not positive
+func returns(candles []types.Candle, lookbacks []int) []float64 {
+	out := make([]float64, len(lookbacks))
+	for i, lookback := range lookbacks {
+		if lookback < 1 || len(candles) <= lookback {
+			return nil
+		}
+		base := candles[len(candles)-1-lookback].Close
+		if base <= 0 {
+			return nil
+		}
+		out[i] = (candles[len(candles)-1].Close/base - 1) * 100
+	}
+	return out
+}
+
+// percentiles places each mover's return on lookback i between 0 (lowest)
+// and 100 (highest); tied returns share their average place
+func percentiles(movers []types.Mover, i int) []float64 {
+	order := make([]int, len(movers))
+	for j := range order {
+		order[j] = j
+	}
+	sort.Slice(order, func(a, b int) bool { return movers[order[a]].Returns[i] < movers[order[b]].Returns[i] })
+
+	out := make([]float64, len(movers))
+	for start := 0; start < len(order); {
+		end := start
+		for end+1 < len(order) && movers[order[end+1]].Returns[i] == movers[order[start]].Returns[i] {
+			end++
+		}
+		place := float64(start+end) / 2 / float64(len(movers)-1) * 100
+		for k := start; k <= end; k++ {
+			out[order[k]] = place
+		}
+		start = end + 1
+	}
+	return out
+}
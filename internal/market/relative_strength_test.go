@@ -0,0 +1,119 @@
+package market
+
+import (
+	"math"
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+func closes(prices ...float64) []types.Candle {
+	candles := make([]types.Candle, len(prices))
+	for i, p := range prices {
+		candles[i] = types.Candle{Open: p, High: p, Low: p, Close: p}
+	}
+	return candles
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestRelativeStrength(t *testing.T) {
+	series := map[string][]types.Candle{
+		"BTCUSDT": closes(100, 100, 101, 102), // +0.99% over 1, +2% over 3
+		"SOLUSDT": closes(100, 105, 108, 110), // best on both
+		"ETHUSDT": closes(100, 99, 99, 100),   // +1.01% over 1, 0% over 3
+		"XRPUSDT": closes(100, 98, 96, 90),    // worst on both
+		"NEWUSDT": closes(100, 110),           // too short for the 3 candle lookback
+	}
+	cfg := config.RelativeStrengthConfig{Lookbacks: []int{1, 3}, Top: 2, Benchmark: "BTCUSDT"}
+
+	report := RelativeStrength("1h", series, cfg)
+	if report == nil {
+		t.Fatal("RelativeStrength() = nil")
+	}
+	if report.Symbols != 4 {
+		t.Errorf("ranked %d symbols, want 4 without the short series", report.Symbols)
+	}
+	if !near(report.BenchmarkReturns[0], (102.0/101-1)*100) || !near(report.BenchmarkReturns[1], 2) {
+		t.Errorf("benchmark returns = %v, want [%v 2]", report.BenchmarkReturns, (102.0/101-1)*100)
+	}
+
+	if len(report.Top) != 2 || report.Top[0].Symbol != "SOLUSDT" {
+		t.Fatalf("top = %+v, want SOLUSDT first", report.Top)
+	}
+	if report.Top[0].Score != 100 {
+		t.Errorf("SOLUSDT score = %v, want 100", report.Top[0].Score)
+	}
+	if !near(report.Top[0].Excess[1], 8) {
+		t.Errorf("SOLUSDT excess over 3 candles = %v, want 8", report.Top[0].Excess[1])
+	}
+
+	if len(report.Bottom) != 2 || report.Bottom[0].Symbol != "XRPUSDT" || report.Bottom[0].Score != 0 {
+		t.Fatalf("bottom = %+v, want XRPUSDT first with score 0", report.Bottom)
+	}
+	for _, top := range report.Top {
+		for _, bottom := range report.Bottom {
+			if top.Symbol == bottom.Symbol {
+				t.Errorf("%s is both a top and a bottom mover", top.Symbol)
+			}
+		}
+	}
+}
+
+func TestRelativeStrength_Ties(t *testing.T) {
+	series := map[string][]types.Candle{
+		"AUSDT": closes(100, 110),
+		"BUSDT": closes(100, 110),
+		"CUSDT": closes(100, 90),
+	}
+	report := RelativeStrength("1h", series, config.RelativeStrengthConfig{Lookbacks: []int{1}, Top: 5})
+
+	// Tied leaders share places 1 and 2 of 0-2
+	if report.Top[0].Score != 75 || report.Top[1].Score != 75 {
+		t.Errorf("tied scores = %v and %v, want 75", report.Top[0].Score, report.Top[1].Score)
+	}
+	if report.BenchmarkReturns != nil || report.Top[0].Excess != nil {
+		t.Error("expected no benchmark comparison without a benchmark series")
+	}
+	if len(report.Top)+len(report.Bottom) != 3 {
+		t.Errorf("got %d top and %d bottom movers for 3 symbols", len(report.Top), len(report.Bottom))
+	}
+}
+
+func TestRelativeStrength_DropsStaleSeries(t *testing.T) {
+	// ending stamps candles hourly up to end
+	ending := func(end int64, candles []types.Candle) []types.Candle {
+		for i := range candles {
+			candles[i].Timestamp = end - int64(len(candles)-1-i)*3600000
+		}
+		return candles
+	}
+	now := int64(1735689600000)
+	series := map[string][]types.Candle{
+		"BTCUSDT":  ending(now-3600000, closes(100, 90)), // stopped an hour ago
+		"ETHUSDT":  ending(now, closes(100, 101)),
+		"SOLUSDT":  ending(now, closes(100, 99)),
+		"DEADUSDT": ending(now-72*3600000, closes(100, 150)), // delisted days ago
+	}
+
+	report := RelativeStrength("1h", series, config.RelativeStrengthConfig{Lookbacks: []int{1}, Top: 1, Benchmark: "BTCUSDT"})
+	if report == nil {
+		t.Fatal("RelativeStrength() = nil")
+	}
+	if report.Symbols != 2 || report.Top[0].Symbol != "ETHUSDT" || report.Bottom[0].Symbol != "SOLUSDT" {
+		t.Errorf("ranked %d symbols, top %s, bottom %s, want ETHUSDT over SOLUSDT alone", report.Symbols, report.Top[0].Symbol, report.Bottom[0].Symbol)
+	}
+	if report.BenchmarkReturns != nil {
+		t.Errorf("benchmark returns = %v, want none from a stale benchmark", report.BenchmarkReturns)
+	}
+}
+
+func TestRelativeStrength_TooFewSymbols(t *testing.T) {
+	series := map[string][]types.Candle{"BTCUSDT": closes(100, 101)}
+	if report := RelativeStrength("1h", series, config.RelativeStrengthConfig{Lookbacks: []int{1}, Top: 5}); report != nil {
+		t.Errorf("RelativeStrength() = %+v, want nil for one symbol", report)
+	}
+}
@@ -1,9 +1,8 @@
 package bybit
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"strconv"
@@ -15,9 +14,14 @@ import (
 	"github.com/letieu/trade-bot/internal/types"
 )
 
+// maxKlineLimit is the largest page the kline endpoint returns
+const maxKlineLimit = 1000
+
 type Client struct {
 	config            *config.BybitConfig
 	client            *http.Client
+	limiter           *rateLimiter
+	retry             retryPolicy
 	cachedSymbols     []string
 	lastSymbolsUpdate time.Time
 	mu                sync.RWMutex
@@ -53,10 +57,27 @@ func NewClient(cfg *config.BybitConfig) *Client {
 		client: &http.Client{
 			Timeout: cfg.Timeout,
 		},
+		limiter: newRateLimiter(cfg.RateLimit),
+		retry:   defaultRetryPolicy,
+	}
+}
+
+// do sends a request once the shared rate limiter allows it
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if err := c.limiter.Wait(req.Context()); err != nil {
+		return nil, err
 	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.limiter.Observe(resp.Header)
+	return resp, nil
 }
 
-func (c *Client) GetSymbols() ([]string, error) {
+func (c *Client) GetSymbols(ctx context.Context) ([]string, error) {
 	c.mu.RLock()
 	if len(c.cachedSymbols) > 0 && time.Since(c.lastSymbolsUpdate) < 24*time.Hour {
 		defer c.mu.RUnlock()
@@ -81,33 +102,9 @@ func (c *Client) GetSymbols() ([]string, error) {
 			url = fmt.Sprintf("%s&cursor=%s", url, cursor)
 		}
 
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
-
-		for key, value := range c.config.Headers {
-			req.Header.Set(key, value)
-		}
-
-		resp, err := c.client.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute request: %w", err)
-		}
-
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			return nil, fmt.Errorf("failed to read response body: %w", err)
-		}
-
 		var instrumentsResp InstrumentsResponse
-		if err := json.Unmarshal(body, &instrumentsResp); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-		}
-
-		if instrumentsResp.RetCode != 0 {
-			return nil, fmt.Errorf("API error: retCode=%d, msg=%s", instrumentsResp.RetCode, instrumentsResp.RetMsg)
+		if err := c.getJSON(ctx, url, &instrumentsResp); err != nil {
+			return nil, err
 		}
 
 		for _, instrument := range instrumentsResp.Result.List {
@@ -120,9 +117,6 @@ func (c *Client) GetSymbols() ([]string, error) {
 		if cursor == "" {
 			break
 		}
-
-		// Avoid hitting rate limits during pagination
-		time.Sleep(100 * time.Millisecond)
 	}
 
 	c.cachedSymbols = symbols
@@ -132,60 +126,95 @@ func (c *Client) GetSymbols() ([]string, error) {
 	return symbols, nil
 }
 
-func (c *Client) doRequestWithRetry(req *http.Request) (*http.Response, error) {
-	var resp *http.Response
-	var err error
-	maxRetries := 3
-
-	for i := 0; i < maxRetries; i++ {
-		resp, err = c.client.Do(req)
-		if err == nil {
-			return resp, nil
-		}
-
-		// Only retry on network errors or timeouts
-		log.Printf("Request failed (attempt %d/%d): %v. Retrying in 2s...", i+1, maxRetries, err)
-		time.Sleep(2 * time.Second)
+// GetCandles returns the latest limit candles ending at endTime (or now when
+// endTime is 0) in chronological order. Limits above Bybit's 1000-candle page
+// size are fetched in several requests.
+func (c *Client) GetCandles(ctx context.Context, symbol, interval string, limit int, endTime int64) ([]types.Candle, error) {
+	if limit <= maxKlineLimit {
+		return c.fetchKlines(ctx, symbol, interval, limit, 0, endTime)
 	}
-	return nil, fmt.Errorf("after %d attempts: %w", maxRetries, err)
+	return c.pageBackwards(ctx, symbol, interval, limit, 0, endTime)
 }
 
-func (c *Client) GetCandles(symbol, interval string, limit int, endTime int64) ([]types.Candle, error) {
-	bybitInterval := mapIntervalToBybit(interval)
-	url := fmt.Sprintf("%s/v5/market/kline?category=linear&symbol=%s&interval=%s&limit=%d",
-		c.config.BaseURL, symbol, bybitInterval, limit)
-
-	if endTime > 0 {
-		url = fmt.Sprintf("%s&end=%d", url, endTime)
+// GetCandleRange returns every candle opened between start and end in
+// chronological order, paging backwards from end as needed.
+func (c *Client) GetCandleRange(ctx context.Context, symbol, interval string, start, end time.Time) ([]types.Candle, error) {
+	if !end.After(start) {
+		return nil, fmt.Errorf("end time %s must be after start time %s", end.Format(time.RFC3339), start.Format(time.RFC3339))
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	duration, err := types.ParseInterval(interval)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
+	limit := int(end.Sub(start)/duration) + 1
+
+	return c.pageBackwards(ctx, symbol, interval, limit, start.UnixMilli(), end.UnixMilli())
+}
+
+// pageBackwards requests up to limit candles in pages of maxKlineLimit, moving
+// the end cursor to just before the oldest candle of each page
+func (c *Client) pageBackwards(ctx context.Context, symbol, interval string, limit int, startTime, endTime int64) ([]types.Candle, error) {
+	var pages [][]types.Candle
+	remaining := limit
+	cursor := endTime
+
+	for remaining > 0 {
+		pageLimit := remaining
+		if pageLimit > maxKlineLimit {
+			pageLimit = maxKlineLimit
+		}
 
-	for key, value := range c.config.Headers {
-		req.Header.Set(key, value)
+		page, err := c.fetchKlines(ctx, symbol, interval, pageLimit, startTime, cursor)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		pages = append(pages, page)
+		remaining -= len(page)
+
+		oldest := page[0].Timestamp
+		if len(page) < pageLimit || (startTime > 0 && oldest <= startTime) {
+			break
+		}
+		cursor = oldest - 1
 	}
 
-	resp, err := c.doRequestWithRetry(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+	// Pages arrive newest first; stitch them oldest first and drop overlaps
+	var candles []types.Candle
+	for i := len(pages) - 1; i >= 0; i-- {
+		for _, candle := range pages[i] {
+			if len(candles) > 0 && candle.Timestamp <= candles[len(candles)-1].Timestamp {
+				continue
+			}
+			candles = append(candles, candle)
+		}
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	if len(candles) > limit {
+		candles = candles[len(candles)-limit:]
 	}
+	return candles, nil
+}
 
-	var klineResp KlineResponse
-	if err := json.Unmarshal(body, &klineResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+// fetchKlines performs a single kline request; limit must not exceed maxKlineLimit
+func (c *Client) fetchKlines(ctx context.Context, symbol, interval string, limit int, startTime, endTime int64) ([]types.Candle, error) {
+	bybitInterval := mapIntervalToBybit(interval)
+	url := fmt.Sprintf("%s/v5/market/kline?category=linear&symbol=%s&interval=%s&limit=%d",
+		c.config.BaseURL, symbol, bybitInterval, limit)
+
+	if startTime > 0 {
+		url = fmt.Sprintf("%s&start=%d", url, startTime)
+	}
+	if endTime > 0 {
+		url = fmt.Sprintf("%s&end=%d", url, endTime)
 	}
 
-	if klineResp.RetCode != 0 {
-		return nil, fmt.Errorf("API error: retCode=%d, msg=%s", klineResp.RetCode, klineResp.RetMsg)
+	var klineResp KlineResponse
+	if err := c.getJSON(ctx, url, &klineResp); err != nil {
+		return nil, err
 	}
 
 	var candles []types.Candle
@@ -284,4 +313,3 @@ func mapIntervalToBybit(interval string) string {
 		return interval
 	}
 }
-
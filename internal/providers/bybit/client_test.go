@@ -0,0 +1,114 @@
+package bybit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/config"
+)
+
+// newKlineServer serves an hourly series of n candles starting at start,
+// mimicking Bybit: newest first, at most limit rows, honouring start/end.
+func newKlineServer(t *testing.T, start time.Time, n int, requests *int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		q := r.URL.Query()
+
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		if limit > maxKlineLimit {
+			t.Errorf("limit %d exceeds page size", limit)
+		}
+		var from, to int64 = 0, 1 << 62
+		if v := q.Get("start"); v != "" {
+			from, _ = strconv.ParseInt(v, 10, 64)
+		}
+		if v := q.Get("end"); v != "" {
+			to, _ = strconv.ParseInt(v, 10, 64)
+		}
+
+		var list [][]string
+		for i := n - 1; i >= 0 && len(list) < limit; i-- {
+			ts := start.Add(time.Duration(i) * time.Hour).UnixMilli()
+			if ts < from || ts > to {
+				continue
+			}
+			price := strconv.Itoa(100 + i)
+			list = append(list, []string{strconv.FormatInt(ts, 10), price, price, price, price, "1", "1"})
+		}
+
+		resp := KlineResponse{}
+		resp.Result.List = list
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestClient_GetCandles_Paginates(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var requests int32
+	server := newKlineServer(t, start, 3000, &requests)
+	defer server.Close()
+
+	client := NewClient(&config.BybitConfig{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	candles, err := client.GetCandles(context.Background(), "BTCUSDT", "1h", 2500, 0)
+	if err != nil {
+		t.Fatalf("GetCandles() error = %v", err)
+	}
+	if len(candles) != 2500 {
+		t.Fatalf("expected 2500 candles, got %d", len(candles))
+	}
+	if want := start.Add(2999 * time.Hour).UnixMilli(); candles[len(candles)-1].Timestamp != want {
+		t.Errorf("last candle = %d, want %d", candles[len(candles)-1].Timestamp, want)
+	}
+	for i := 1; i < len(candles); i++ {
+		if candles[i].Timestamp-candles[i-1].Timestamp != time.Hour.Milliseconds() {
+			t.Fatalf("series not contiguous at %d", i)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestClient_GetCandleRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var requests int32
+	server := newKlineServer(t, start, 3000, &requests)
+	defer server.Close()
+
+	client := NewClient(&config.BybitConfig{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	from := start.Add(100 * time.Hour)
+	to := start.Add(1600 * time.Hour)
+	candles, err := client.GetCandleRange(context.Background(), "BTCUSDT", "1h", from, to)
+	if err != nil {
+		t.Fatalf("GetCandleRange() error = %v", err)
+	}
+	if len(candles) != 1501 {
+		t.Fatalf("expected 1501 candles, got %d", len(candles))
+	}
+	if candles[0].Timestamp != from.UnixMilli() || candles[len(candles)-1].Timestamp != to.UnixMilli() {
+		t.Errorf("range = [%d, %d], want [%d, %d]", candles[0].Timestamp, candles[len(candles)-1].Timestamp, from.UnixMilli(), to.UnixMilli())
+	}
+
+	// History shorter than requested stops early instead of looping
+	requests = 0
+	candles, err = client.GetCandleRange(context.Background(), "BTCUSDT", "1h", start.Add(-2000*time.Hour), start.Add(10*time.Hour))
+	if err != nil {
+		t.Fatalf("GetCandleRange() error = %v", err)
+	}
+	if len(candles) != 11 {
+		t.Errorf("expected 11 candles, got %d", len(candles))
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
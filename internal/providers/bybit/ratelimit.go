@@ -0,0 +1,127 @@
+package bybit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxRateLimitPause caps how long an exhausted quota window can hold requests
+const maxRateLimitPause = time.Minute
+
+// rateLimiter is a token bucket shared by every request made through a Client.
+// It refills at rate tokens per second up to burst, and additionally pauses all
+// requests when Bybit reports that the current quota window is exhausted.
+type rateLimiter struct {
+	mu           sync.Mutex
+	rate         float64
+	burst        float64
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+func newRateLimiter(perSecond int) *rateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		rate:   float64(perSecond),
+		burst:  float64(perSecond),
+		tokens: float64(perSecond),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent or ctx is done
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	for {
+		delay := l.reserve(time.Now())
+		if delay <= 0 {
+			return nil
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// sleep waits for d unless ctx is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token if one is available and otherwise returns how long to wait
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Before(l.blockedUntil) {
+		return l.blockedUntil.Sub(now)
+	}
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Observe adapts to Bybit's rate limit headers. When the remaining quota in the
+// current window reaches zero, every request is held until the window resets.
+func (l *rateLimiter) Observe(header http.Header) {
+	if l == nil {
+		return
+	}
+
+	remaining, err := strconv.Atoi(header.Get("X-Bapi-Limit-Status"))
+	if err != nil || remaining > 0 {
+		return
+	}
+
+	resetMs, err := strconv.ParseInt(header.Get("X-Bapi-Limit-Reset-Timestamp"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	// Guard against clock skew or a bogus header stalling every request
+	reset := time.UnixMilli(resetMs)
+	if limit := time.Now().Add(maxRateLimitPause); reset.After(limit) {
+		reset = limit
+	}
+	l.blockUntil(reset)
+}
+
+func (l *rateLimiter) blockUntil(t time.Time) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if t.After(l.blockedUntil) {
+		l.blockedUntil = t
+		l.tokens = 0
+	}
+}
@@ -0,0 +1,86 @@
+package bybit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	l := newRateLimiter(2)
+	now := l.last
+
+	// The bucket starts full
+	for i := 0; i < 2; i++ {
+		if d := l.reserve(now); d != 0 {
+			t.Fatalf("request %d should pass immediately, got wait %v", i, d)
+		}
+	}
+
+	if d := l.reserve(now); d != 500*time.Millisecond {
+		t.Errorf("expected 500ms wait on empty bucket, got %v", d)
+	}
+	if d := l.reserve(now.Add(500 * time.Millisecond)); d != 0 {
+		t.Errorf("expected a token after 500ms, got wait %v", d)
+	}
+}
+
+func TestRateLimiter_SharedAcrossGoroutines(t *testing.T) {
+	l := newRateLimiter(50)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				l.Wait(context.Background())
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 80 requests with a burst of 50 need at least 30 refills at 50/s
+	if elapsed := time.Since(start); elapsed < 550*time.Millisecond {
+		t.Errorf("80 requests at 50/s finished too quickly: %v", elapsed)
+	}
+}
+
+func TestRateLimiter_ObserveExhaustedWindow(t *testing.T) {
+	l := newRateLimiter(100)
+	reset := time.Now().Add(2 * time.Second)
+
+	header := http.Header{}
+	header.Set("X-Bapi-Limit-Status", "5")
+	header.Set("X-Bapi-Limit-Reset-Timestamp", strconv.FormatInt(reset.UnixMilli(), 10))
+	l.Observe(header)
+	if d := l.reserve(time.Now()); d != 0 {
+		t.Fatalf("quota remaining should not block, got wait %v", d)
+	}
+
+	header.Set("X-Bapi-Limit-Status", "0")
+	l.Observe(header)
+	if d := l.reserve(time.Now()); d <= time.Second {
+		t.Errorf("exhausted quota should block until reset, got wait %v", d)
+	}
+
+	var nilLimiter *rateLimiter
+	nilLimiter.Observe(header)
+	nilLimiter.Wait(context.Background())
+}
+
+func TestRateLimiter_WaitCancelled(t *testing.T) {
+	l := newRateLimiter(1)
+	l.blockUntil(time.Now().Add(time.Minute))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
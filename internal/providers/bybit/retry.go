@@ -0,0 +1,181 @@
+package bybit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryPolicy controls how a single API call is retried
+type retryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Budget      time.Duration // total time one call may spend waiting between attempts
+}
+
+var defaultRetryPolicy = retryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+	Budget:      30 * time.Second,
+}
+
+const retCodeTooManyVisits = 10006
+
+// Bybit retCodes that indicate a transient condition worth retrying
+var retryableRetCodes = map[int]bool{
+	10000:                true, // server timeout
+	retCodeTooManyVisits: true,
+	10016:                true, // internal server error
+}
+
+// APIError is a non-zero retCode returned by Bybit
+type APIError struct {
+	RetCode int
+	RetMsg  string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: retCode=%d, msg=%s", e.RetCode, e.RetMsg)
+}
+
+// apiResponse is implemented by every Bybit response envelope
+type apiResponse interface {
+	status() (int, string)
+}
+
+func (r *InstrumentsResponse) status() (int, string) { return r.RetCode, r.RetMsg }
+func (r *KlineResponse) status() (int, string)       { return r.RetCode, r.RetMsg }
+
+// backoff returns the delay before the given retry (1-based) using
+// exponential growth with equal jitter
+func (p retryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay << (retry - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// getJSON fetches url into out, retrying transport errors, HTTP 429/5xx and
+// throttling retCodes. A fresh request is built for every attempt, and
+// cancelling ctx aborts both the in-flight request and any pending retry.
+func (c *Client) getJSON(ctx context.Context, url string, out apiResponse) error {
+	policy := c.retry
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+
+	var lastErr error
+	var waited time.Duration
+	attempt := 0
+
+	for attempt < policy.MaxAttempts {
+		attempt++
+
+		result, err := c.attempt(ctx, url, out)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if !result.retryable || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			break
+		}
+
+		delay := policy.backoff(attempt)
+		if result.retryAfter > delay {
+			delay = result.retryAfter
+		}
+		if waited+delay > policy.Budget {
+			log.Printf("Retry budget exhausted for %s after %d attempts", url, attempt)
+			break
+		}
+
+		// Being throttled affects every caller, so hold the shared limiter too
+		if result.throttled {
+			c.limiter.blockUntil(time.Now().Add(delay))
+		}
+
+		log.Printf("Request failed (attempt %d/%d): %v. Retrying in %v...", attempt, policy.MaxAttempts, err, delay.Round(time.Millisecond))
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+		waited += delay
+	}
+
+	if attempt == 1 {
+		return lastErr
+	}
+	return fmt.Errorf("after %d attempts: %w", attempt, lastErr)
+}
+
+// attemptResult classifies a failed attempt
+type attemptResult struct {
+	retryable  bool
+	throttled  bool
+	retryAfter time.Duration // server-requested delay, if any
+}
+
+// attempt performs one request and classifies any failure
+func (c *Client) attempt(ctx context.Context, url string, out apiResponse) (attemptResult, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return attemptResult{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for key, value := range c.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		// Network errors and timeouts
+		return attemptResult{retryable: true}, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return attemptResult{retryable: true}, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		throttled := resp.StatusCode == http.StatusTooManyRequests
+		return attemptResult{
+			retryable:  throttled || resp.StatusCode >= 500,
+			throttled:  throttled,
+			retryAfter: retryAfter(resp.Header),
+		}, fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return attemptResult{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if code, msg := out.status(); code != 0 {
+		return attemptResult{
+			retryable: retryableRetCodes[code],
+			throttled: code == retCodeTooManyVisits,
+		}, &APIError{RetCode: code, RetMsg: msg}
+	}
+
+	return attemptResult{}, nil
+}
+
+// retryAfter parses a Retry-After header given in seconds
+func retryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,202 @@
+package bybit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/config"
+)
+
+var fastRetry = retryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   time.Millisecond,
+	MaxDelay:    5 * time.Millisecond,
+	Budget:      time.Second,
+}
+
+// newScriptedServer replies with the given responses in order, repeating the last one
+func newScriptedServer(responses ...func(w http.ResponseWriter)) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(&calls, 1)) - 1
+		if i >= len(responses) {
+			i = len(responses) - 1
+		}
+		responses[i](w)
+	}))
+	return server, &calls
+}
+
+func status(code int) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) { w.WriteHeader(code) }
+}
+
+func body(s string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) { w.Write([]byte(s)) }
+}
+
+const okKlines = `{"retCode":0,"retMsg":"OK","result":{"list":[["1704067200000","1","2","0.5","1.5","10","10"]]}}`
+
+func TestClient_RetryClassification(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []func(w http.ResponseWriter)
+		wantErr   bool
+		wantCalls int32
+	}{
+		{
+			name:      "429 then success",
+			responses: []func(w http.ResponseWriter){status(http.StatusTooManyRequests), body(okKlines)},
+			wantCalls: 2,
+		},
+		{
+			name:      "503 twice then success",
+			responses: []func(w http.ResponseWriter){status(503), status(503), body(okKlines)},
+			wantCalls: 3,
+		},
+		{
+			name:      "retCode 10006 then success",
+			responses: []func(w http.ResponseWriter){body(`{"retCode":10006,"retMsg":"Too many visits!"}`), body(okKlines)},
+			wantCalls: 2,
+		},
+		{
+			name:      "invalid symbol is not retried",
+			responses: []func(w http.ResponseWriter){body(`{"retCode":10001,"retMsg":"params error"}`)},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "403 is not retried",
+			responses: []func(w http.ResponseWriter){status(http.StatusForbidden)},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "gives up after max attempts",
+			responses: []func(w http.ResponseWriter){status(502)},
+			wantErr:   true,
+			wantCalls: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := newScriptedServer(tt.responses...)
+			defer server.Close()
+
+			client := NewClient(&config.BybitConfig{BaseURL: server.URL, Timeout: time.Second})
+			client.retry = fastRetry
+
+			candles, err := client.GetCandles(context.Background(), "BTCUSDT", "1h", 1, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetCandles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(candles) != 1 {
+				t.Errorf("expected 1 candle, got %d", len(candles))
+			}
+			if got := atomic.LoadInt32(calls); got != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestClient_RetryBudget(t *testing.T) {
+	server, calls := newScriptedServer(func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	defer server.Close()
+
+	client := NewClient(&config.BybitConfig{BaseURL: server.URL, Timeout: time.Second})
+	client.retry = fastRetry
+	client.retry.Budget = 100 * time.Millisecond
+
+	_, err := client.GetCandles(context.Background(), "BTCUSDT", "1h", 1, 0)
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("expected 429 error, got %v", err)
+	}
+	// A 1s Retry-After does not fit in a 100ms budget
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("expected 1 call within budget, got %d", got)
+	}
+}
+
+func TestClient_GetSymbolsRetries(t *testing.T) {
+	server, calls := newScriptedServer(
+		status(http.StatusServiceUnavailable),
+		body(`{"retCode":0,"result":{"list":[{"symbol":"BTCUSDT","status":"Trading"},{"symbol":"BTCUSDC","status":"Trading"}]}}`),
+	)
+	defer server.Close()
+
+	client := NewClient(&config.BybitConfig{BaseURL: server.URL, Timeout: time.Second})
+	client.retry = fastRetry
+
+	symbols, err := client.GetSymbols(context.Background())
+	if err != nil {
+		t.Fatalf("GetSymbols() error = %v", err)
+	}
+	if len(symbols) != 1 || symbols[0] != "BTCUSDT" {
+		t.Errorf("unexpected symbols %v", symbols)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("expected 2 calls, got %d", got)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	server, _ := newScriptedServer(body(`{"retCode":10001,"retMsg":"params error"}`))
+	defer server.Close()
+
+	client := NewClient(&config.BybitConfig{BaseURL: server.URL, Timeout: time.Second})
+	client.retry = fastRetry
+
+	_, err := client.GetCandles(context.Background(), "BTCUSDT", "1h", 1, 0)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RetCode != 10001 {
+		t.Errorf("expected APIError with retCode 10001, got %v", err)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := retryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for retry := 1; retry <= 10; retry++ {
+		want := p.BaseDelay << (retry - 1)
+		if want > p.MaxDelay {
+			want = p.MaxDelay
+		}
+		got := p.backoff(retry)
+		if got < want/2 || got > want {
+			t.Errorf("backoff(%d) = %v, want within [%v, %v]", retry, got, want/2, want)
+		}
+	}
+}
+
+func TestClient_CancelStopsRetries(t *testing.T) {
+	server, calls := newScriptedServer(status(http.StatusServiceUnavailable))
+	defer server.Close()
+
+	client := NewClient(&config.BybitConfig{BaseURL: server.URL, Timeout: time.Second})
+	client.retry = retryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Second, Budget: time.Minute}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetCandles(ctx, "BTCUSDT", "1h", 1, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("cancellation did not interrupt backoff, took %v", elapsed)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("expected 1 call before cancellation, got %d", got)
+	}
+}
@@ -0,0 +1,201 @@
+package candlestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// Source is a market data provider that can also fetch a time range longer
+// than one request, such as bybit.Client
+type Source interface {
+	types.MarketDataProvider
+	GetCandleRange(ctx context.Context, symbol, interval string, start, end time.Time) ([]types.Candle, error)
+}
+
+// Provider serves candles from a Store and only asks the source provider for
+// ranges that are not on disk yet. Closed candles fetched from the source are
+// written back to the store, along with the listing start once it is found so
+// the range before a listing is not asked for again.
+type Provider struct {
+	store  *Store
+	source Source
+}
+
+func NewProvider(store *Store, source Source) *Provider {
+	return &Provider{
+		store:  store,
+		source: source,
+	}
+}
+
+func (p *Provider) GetSymbols(ctx context.Context) ([]string, error) {
+	return p.source.GetSymbols(ctx)
+}
+
+// GetCandles returns up to limit candles ending at endTime (or now when endTime is 0).
+// Unlike a single exchange request, limit is not capped.
+func (p *Provider) GetCandles(ctx context.Context, symbol, interval string, limit int, endTime int64) ([]types.Candle, error) {
+	duration, err := types.ParseInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	end := endTime
+	if end <= 0 {
+		end = time.Now().UnixMilli()
+	}
+	from := end - int64(limit)*duration.Milliseconds()
+
+	candles, _, err := p.ensure(ctx, symbol, interval, from, end, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+	return candles, nil
+}
+
+// Sync makes sure every closed candle since the given time is stored and
+// returns how many new candles were written
+func (p *Provider) Sync(ctx context.Context, symbol, interval string, since time.Time) (int, error) {
+	duration, err := types.ParseInterval(interval)
+	if err != nil {
+		return 0, err
+	}
+
+	_, written, err := p.ensure(ctx, symbol, interval, since.UnixMilli(), time.Now().UnixMilli(), duration)
+	return written, err
+}
+
+// ensure returns the candles opened in [from, end], fetching and storing any gaps
+func (p *Provider) ensure(ctx context.Context, symbol, interval string, from, end int64, duration time.Duration) ([]types.Candle, int, error) {
+	stored, err := p.store.Load(symbol, interval)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	listed, err := p.store.ListedAt(symbol, interval)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	window := inRange(stored, from, end)
+	head := max(from, listed)
+	gaps := missingRanges(window, head, end, duration.Milliseconds())
+	if len(gaps) == 0 {
+		return window, 0, nil
+	}
+
+	written := 0
+	byTime := make(map[int64]types.Candle, len(window))
+	for _, c := range window {
+		byTime[c.Timestamp] = c
+	}
+
+	closedBefore := time.Now().UnixMilli()
+	for _, gap := range gaps {
+		fetched, err := p.fetchRange(ctx, symbol, interval, gap.from, gap.to)
+		if err != nil {
+			return nil, written, fmt.Errorf("failed to fetch %s %s: %w", symbol, interval, err)
+		}
+
+		// Klines run without breaks once a symbol is listed, so a gap at the head
+		// that is missing its first candles means there is nothing before them
+		if gap.from == head {
+			listedAt := int64(0)
+			switch {
+			case len(fetched) > 0 && fetched[0].Timestamp-gap.from >= duration.Milliseconds():
+				listedAt = fetched[0].Timestamp
+			case len(fetched) == 0 && len(window) > 0:
+				listedAt = window[0].Timestamp
+			}
+			if listedAt > 0 {
+				if err := p.store.SetListedAt(symbol, interval, listedAt); err != nil {
+					return nil, written, err
+				}
+			}
+		}
+
+		// Only closed candles are persisted; the forming one is served but not stored
+		var closed []types.Candle
+		for _, c := range fetched {
+			byTime[c.Timestamp] = c
+			if c.Timestamp+duration.Milliseconds() <= closedBefore {
+				closed = append(closed, c)
+			}
+		}
+
+		n, err := p.store.Append(symbol, interval, closed)
+		written += n
+		if err != nil {
+			return nil, written, err
+		}
+	}
+
+	merged := make([]types.Candle, 0, len(byTime))
+	for _, c := range byTime {
+		merged = append(merged, c)
+	}
+	sortCandles(merged)
+
+	return inRange(merged, from, end), written, nil
+}
+
+// fetchRange returns the candles opened in [from, to]
+func (p *Provider) fetchRange(ctx context.Context, symbol, interval string, from, to int64) ([]types.Candle, error) {
+	// The source needs a non-empty range; inRange trims the extra millisecond
+	candles, err := p.source.GetCandleRange(ctx, symbol, interval, time.UnixMilli(from), time.UnixMilli(max(to, from+1)))
+	if err != nil {
+		return nil, err
+	}
+	return inRange(dedupe(candles), from, to), nil
+}
+
+type timeRange struct {
+	from, to int64
+}
+
+// missingRanges finds the spans of [from, end] not covered by candles, which
+// must be sorted and lie within the range
+func missingRanges(candles []types.Candle, from, end, step int64) []timeRange {
+	var gaps []timeRange
+	cursor := from
+	for _, c := range candles {
+		if c.Timestamp-cursor >= step {
+			gaps = append(gaps, timeRange{from: cursor, to: c.Timestamp - 1})
+		}
+		cursor = c.Timestamp + step
+	}
+	if cursor <= end {
+		gaps = append(gaps, timeRange{from: cursor, to: end})
+	}
+	return gaps
+}
+
+func inRange(candles []types.Candle, from, to int64) []types.Candle {
+	var result []types.Candle
+	for _, c := range candles {
+		if c.Timestamp >= from && c.Timestamp <= to {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func dedupe(candles []types.Candle) []types.Candle {
+	sortCandles(candles)
+	result := candles[:0:0]
+	for _, c := range candles {
+		if len(result) > 0 && result[len(result)-1].Timestamp == c.Timestamp {
+			result[len(result)-1] = c
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
@@ -0,0 +1,198 @@
+package candlestore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// pagedSource serves a fixed hourly series the way Bybit does: the newest
+// limit candles opened at or before endTime, or every candle in a range.
+type pagedSource struct {
+	candles  []types.Candle
+	requests int
+}
+
+func (s *pagedSource) GetSymbols(ctx context.Context) ([]string, error) {
+	return []string{"BTCUSDT"}, nil
+}
+
+func (s *pagedSource) GetCandles(ctx context.Context, symbol, interval string, limit int, endTime int64) ([]types.Candle, error) {
+	s.requests++
+	var page []types.Candle
+	for _, c := range s.candles {
+		if c.Timestamp <= endTime {
+			page = append(page, c)
+		}
+	}
+	if len(page) > limit {
+		page = page[len(page)-limit:]
+	}
+	return page, nil
+}
+
+func (s *pagedSource) GetCandleRange(ctx context.Context, symbol, interval string, start, end time.Time) ([]types.Candle, error) {
+	s.requests++
+	var candles []types.Candle
+	for _, c := range s.candles {
+		if c.Timestamp >= start.UnixMilli() && c.Timestamp <= end.UnixMilli() {
+			candles = append(candles, c)
+		}
+	}
+	return candles, nil
+}
+
+func hourlySeries(start time.Time, n int) []types.Candle {
+	candles := make([]types.Candle, n)
+	for i := range candles {
+		price := float64(100 + i)
+		candles[i] = types.Candle{
+			Timestamp: start.Add(time.Duration(i) * time.Hour).UnixMilli(),
+			Open:      price,
+			High:      price + 1,
+			Low:       price - 1,
+			Close:     price + 0.5,
+			Volume:    10,
+			Symbol:    "BTCUSDT",
+			Interval:  "1h",
+		}
+	}
+	return candles
+}
+
+func TestProvider_GetCandles_Caches(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := &pagedSource{candles: hourlySeries(start, 2500)}
+	provider := NewProvider(NewStore(t.TempDir()), source)
+
+	end := start.Add(2499 * time.Hour).UnixMilli()
+	candles, err := provider.GetCandles(context.Background(), "BTCUSDT", "1h", 2500, end)
+	if err != nil {
+		t.Fatalf("GetCandles() error = %v", err)
+	}
+	if len(candles) != 2500 {
+		t.Fatalf("expected 2500 candles, got %d", len(candles))
+	}
+	for i := 1; i < len(candles); i++ {
+		if candles[i].Timestamp-candles[i-1].Timestamp != time.Hour.Milliseconds() {
+			t.Fatalf("series not contiguous at %d", i)
+		}
+	}
+	if source.requests != 1 {
+		t.Errorf("expected a single range request, got %d", source.requests)
+	}
+
+	// Everything is closed and stored now, so a second call must not hit the source
+	source.requests = 0
+	again, err := provider.GetCandles(context.Background(), "BTCUSDT", "1h", 100, end)
+	if err != nil {
+		t.Fatalf("GetCandles() error = %v", err)
+	}
+	if len(again) != 100 || again[99].Timestamp != end {
+		t.Errorf("expected the last 100 candles ending at %d, got %d ending at %d", end, len(again), again[len(again)-1].Timestamp)
+	}
+	if source.requests != 0 {
+		t.Errorf("expected cached candles to be served from disk, got %d requests", source.requests)
+	}
+}
+
+func TestProvider_FetchesOnlyMissingRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	series := hourlySeries(start, 200)
+	store := NewStore(t.TempDir())
+	if _, err := store.Append("BTCUSDT", "1h", series[:150]); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	source := &pagedSource{candles: series}
+	provider := NewProvider(store, source)
+
+	end := series[199].Timestamp
+	candles, err := provider.GetCandles(context.Background(), "BTCUSDT", "1h", 100, end)
+	if err != nil {
+		t.Fatalf("GetCandles() error = %v", err)
+	}
+	if len(candles) != 100 || candles[0].Timestamp != series[100].Timestamp {
+		t.Fatalf("expected candles 100..199, got %d starting at %d", len(candles), candles[0].Timestamp)
+	}
+	if source.requests != 1 {
+		t.Errorf("expected a single top-up request, got %d", source.requests)
+	}
+
+	stored, _ := store.Load("BTCUSDT", "1h")
+	if len(stored) != 200 {
+		t.Errorf("expected 200 stored candles, got %d", len(stored))
+	}
+}
+
+func TestProvider_RemembersListing(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := &pagedSource{candles: hourlySeries(start, 50)}
+	dir := t.TempDir()
+	provider := NewProvider(NewStore(dir), source)
+
+	// Asks for 100 candles of a symbol listed 50 candles ago
+	end := start.Add(49 * time.Hour).UnixMilli()
+	candles, err := provider.GetCandles(context.Background(), "BTCUSDT", "1h", 100, end)
+	if err != nil {
+		t.Fatalf("GetCandles() error = %v", err)
+	}
+	if len(candles) != 50 {
+		t.Fatalf("expected the 50 listed candles, got %d", len(candles))
+	}
+
+	// The range before the listing has nothing, so it must not be asked for again
+	source.requests = 0
+	for i := 0; i < 3; i++ {
+		if _, err := provider.GetCandles(context.Background(), "BTCUSDT", "1h", 100, end); err != nil {
+			t.Fatalf("GetCandles() error = %v", err)
+		}
+	}
+	if source.requests != 0 {
+		t.Errorf("expected the leading gap to be skipped, got %d requests", source.requests)
+	}
+
+	// The listing is kept with the store, so a restart does not ask again either
+	restarted := NewProvider(NewStore(dir), source)
+	candles, err = restarted.GetCandles(context.Background(), "BTCUSDT", "1h", 100, end)
+	if err != nil {
+		t.Fatalf("GetCandles() error = %v", err)
+	}
+	if len(candles) != 50 || source.requests != 0 {
+		t.Errorf("after restart got %d candles with %d requests, want 50 with none", len(candles), source.requests)
+	}
+}
+
+func TestStore_SkipsTornLine(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	series := hourlySeries(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 3)
+
+	if _, err := store.Append("BTCUSDT", "1h", series[:2]); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	// Simulate a write interrupted mid-line
+	f, err := os.OpenFile(filepath.Join(dir, "1h", "BTCUSDT.csv"), os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("1704070800000,10")
+	f.Close()
+
+	if _, err := store.Append("BTCUSDT", "1h", series); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	candles, err := store.Load("BTCUSDT", "1h")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(candles) != 3 {
+		t.Errorf("expected 3 candles, got %d", len(candles))
+	}
+}
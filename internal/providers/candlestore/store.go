@@ -0,0 +1,281 @@
+package candlestore
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// Store keeps candles on disk in one append-only CSV file per symbol and interval:
+//
+//	<dir>/<interval>/<SYMBOL>.csv
+//
+// Each line is "timestamp,open,high,low,close,volume". Lines may be out of
+// order after a backfill; Load always returns a sorted, de-duplicated series.
+//
+// Once the first candle after a listing is known, its timestamp is kept next
+// to the candles in <dir>/<interval>/<SYMBOL>.listed.
+type Store struct {
+	dir string
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func NewStore(dir string) *Store {
+	return &Store{
+		dir:   dir,
+		locks: make(map[string]*sync.Mutex),
+	}
+}
+
+func (s *Store) path(symbol, interval string) string {
+	return filepath.Join(s.dir, interval, symbol+".csv")
+}
+
+func (s *Store) listedPath(symbol, interval string) string {
+	return filepath.Join(s.dir, interval, symbol+".listed")
+}
+
+// lock returns the mutex guarding a single symbol/interval file
+func (s *Store) lock(symbol, interval string) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := interval + "/" + symbol
+	l, ok := s.locks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		s.locks[key] = l
+	}
+	return l
+}
+
+// Load reads every stored candle for symbol and interval in chronological order
+func (s *Store) Load(symbol, interval string) ([]types.Candle, error) {
+	l := s.lock(symbol, interval)
+	l.Lock()
+	defer l.Unlock()
+
+	return s.load(symbol, interval)
+}
+
+func (s *Store) load(symbol, interval string) ([]types.Candle, error) {
+	f, err := os.Open(s.path(symbol, interval))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open candle file: %w", err)
+	}
+	defer f.Close()
+
+	seen := make(map[int64]int)
+	var candles []types.Candle
+
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		candle, err := parseLine(text)
+		if err != nil {
+			// A torn write should not make the whole file unreadable
+			log.Printf("Skipping malformed candle in %s line %d: %v", s.path(symbol, interval), line, err)
+			continue
+		}
+		candle.Symbol = symbol
+		candle.Interval = interval
+
+		// Later lines win so a re-fetched candle replaces the stored one
+		if i, ok := seen[candle.Timestamp]; ok {
+			candles[i] = candle
+			continue
+		}
+		seen[candle.Timestamp] = len(candles)
+		candles = append(candles, candle)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read candle file: %w", err)
+	}
+
+	sortCandles(candles)
+	return candles, nil
+}
+
+func sortCandles(candles []types.Candle) {
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].Timestamp < candles[j].Timestamp
+	})
+}
+
+// Append stores candles that are not already on disk and returns how many were written
+func (s *Store) Append(symbol, interval string, candles []types.Candle) (int, error) {
+	if len(candles) == 0 {
+		return 0, nil
+	}
+
+	l := s.lock(symbol, interval)
+	l.Lock()
+	defer l.Unlock()
+
+	existing, err := s.load(symbol, interval)
+	if err != nil {
+		return 0, err
+	}
+	stored := make(map[int64]bool, len(existing))
+	for _, c := range existing {
+		stored[c.Timestamp] = true
+	}
+
+	path := s.path(symbol, interval)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open candle file: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+
+	// Start on a fresh line if a previous write was interrupted mid-line
+	if torn, err := missingTrailingNewline(f); err != nil {
+		return 0, err
+	} else if torn {
+		w.WriteString("\n")
+	}
+
+	written := 0
+	for _, c := range candles {
+		if stored[c.Timestamp] {
+			continue
+		}
+		stored[c.Timestamp] = true
+		if _, err := w.WriteString(formatLine(c)); err != nil {
+			return written, fmt.Errorf("failed to write candle: %w", err)
+		}
+		written++
+	}
+
+	if err := w.Flush(); err != nil {
+		return written, fmt.Errorf("failed to write candles: %w", err)
+	}
+	return written, nil
+}
+
+// ListedAt returns the first candle recorded for symbol and interval by
+// SetListedAt, or 0 when none has been
+func (s *Store) ListedAt(symbol, interval string) (int64, error) {
+	l := s.lock(symbol, interval)
+	l.Lock()
+	defer l.Unlock()
+
+	data, err := os.ReadFile(s.listedPath(symbol, interval))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read listing file: %w", err)
+	}
+
+	timestamp, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		// Treat a damaged file as unknown; the listing is found again on the next fetch
+		log.Printf("Ignoring malformed listing in %s: %v", s.listedPath(symbol, interval), err)
+		return 0, nil
+	}
+	return timestamp, nil
+}
+
+// SetListedAt records the first candle of symbol and interval, before which
+// the source has no data
+func (s *Store) SetListedAt(symbol, interval string, timestamp int64) error {
+	l := s.lock(symbol, interval)
+	l.Lock()
+	defer l.Unlock()
+
+	path := s.listedPath(symbol, interval)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	// Write then rename so a reader never sees a half-written timestamp
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(timestamp, 10)+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write listing file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write listing file: %w", err)
+	}
+	return nil
+}
+
+func missingTrailingNewline(f *os.File) (bool, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return false, fmt.Errorf("failed to stat candle file: %w", err)
+	}
+	if info.Size() == 0 {
+		return false, nil
+	}
+
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read candle file: %w", err)
+	}
+	return last[0] != '\n', nil
+}
+
+func formatLine(c types.Candle) string {
+	return fmt.Sprintf("%d,%s,%s,%s,%s,%s\n", c.Timestamp,
+		strconv.FormatFloat(c.Open, 'f', -1, 64),
+		strconv.FormatFloat(c.High, 'f', -1, 64),
+		strconv.FormatFloat(c.Low, 'f', -1, 64),
+		strconv.FormatFloat(c.Close, 'f', -1, 64),
+		strconv.FormatFloat(c.Volume, 'f', -1, 64))
+}
+
+func parseLine(line string) (types.Candle, error) {
+	fields := strings.Split(line, ",")
+	if len(fields) != 6 {
+		return types.Candle{}, fmt.Errorf("expected 6 fields, got %d", len(fields))
+	}
+
+	timestamp, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return types.Candle{}, fmt.Errorf("invalid timestamp: %w", err)
+	}
+
+	values := make([]float64, 5)
+	for i, field := range fields[1:] {
+		values[i], err = strconv.ParseFloat(field, 64)
+		if err != nil {
+			return types.Candle{}, fmt.Errorf("invalid number %q: %w", field, err)
+		}
+	}
+
+	return types.Candle{
+		Timestamp: timestamp,
+		Open:      values[0],
+		High:      values[1],
+		Low:       values[2],
+		Close:     values[3],
+		Volume:    values[4],
+	}, nil
+}
@@ -0,0 +1,248 @@
+package rules
+
+import (
+	"math"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// Every value is evaluated at a candle index. Booleans are 1 and 0, and NaN
+// means "unknown", e.g. an indicator still warming up or an offset before the
+// first candle. Unknown values make comparisons unknown, and a rule only
+// matches when it evaluates to a definite true.
+
+type valueType int
+
+const (
+	numberType valueType = iota
+	boolType
+	colourType
+)
+
+func (t valueType) String() string {
+	switch t {
+	case boolType:
+		return "a condition"
+	case colourType:
+		return "a colour"
+	}
+	return "a number"
+}
+
+type node interface {
+	eval(e *env, i int) float64
+	typ() valueType
+	// span is the number of candles, ending at the evaluated one, the node reads
+	span() int
+}
+
+// env holds the candles a rule is evaluated on and the series computed so far
+type env struct {
+	candles []types.Candle
+	series  map[*callNode][]float64
+}
+
+func newEnv(candles []types.Candle) *env {
+	return &env{candles: candles, series: make(map[*callNode][]float64)}
+}
+
+func (e *env) inRange(i int) bool {
+	return i >= 0 && i < len(e.candles)
+}
+
+type numberNode struct {
+	value float64
+}
+
+func (n *numberNode) eval(e *env, i int) float64 { return n.value }
+func (n *numberNode) typ() valueType             { return numberType }
+func (n *numberNode) span() int                  { return 1 }
+
+var fields = map[string]func(c types.Candle) float64{
+	"open":   func(c types.Candle) float64 { return c.Open },
+	"high":   func(c types.Candle) float64 { return c.High },
+	"low":    func(c types.Candle) float64 { return c.Low },
+	"close":  func(c types.Candle) float64 { return c.Close },
+	"volume": func(c types.Candle) float64 { return c.Volume },
+	"body":   func(c types.Candle) float64 { return math.Abs(c.Close - c.Open) },
+	"range":  func(c types.Candle) float64 { return c.High - c.Low },
+}
+
+type fieldNode struct {
+	get func(c types.Candle) float64
+}
+
+func (n *fieldNode) eval(e *env, i int) float64 {
+	if !e.inRange(i) {
+		return math.NaN()
+	}
+	return n.get(e.candles[i])
+}
+func (n *fieldNode) typ() valueType { return numberType }
+func (n *fieldNode) span() int      { return 1 }
+
+var colours = map[string]types.CandleColor{
+	"red":   types.ColorRed,
+	"green": types.ColorGreen,
+}
+
+// colourNode is only valid as an argument, so it is never evaluated
+type colourNode struct {
+	colour types.CandleColor
+}
+
+func (n *colourNode) eval(e *env, i int) float64 { return math.NaN() }
+func (n *colourNode) typ() valueType             { return colourType }
+func (n *colourNode) span() int                  { return 0 }
+
+// offsetNode evaluates x a number of candles back, as in close[1]
+type offsetNode struct {
+	x      node
+	offset int
+}
+
+func (n *offsetNode) eval(e *env, i int) float64 { return n.x.eval(e, i-n.offset) }
+func (n *offsetNode) typ() valueType             { return n.x.typ() }
+func (n *offsetNode) span() int                  { return n.x.span() + n.offset }
+
+type unaryNode struct {
+	op string // "-" or "not"
+	x  node
+}
+
+func (n *unaryNode) eval(e *env, i int) float64 {
+	v := n.x.eval(e, i)
+	if n.op == "-" || math.IsNaN(v) {
+		return -v
+	}
+	return boolValue(v == 0)
+}
+
+func (n *unaryNode) typ() valueType {
+	if n.op == "not" {
+		return boolType
+	}
+	return numberType
+}
+func (n *unaryNode) span() int { return n.x.span() }
+
+type binaryNode struct {
+	op   string
+	l, r node
+}
+
+func (n *binaryNode) eval(e *env, i int) float64 {
+	l := n.l.eval(e, i)
+
+	// and/or only need the right side when the left does not decide
+	switch n.op {
+	case "and":
+		if l == 0 {
+			return 0
+		}
+		r := n.r.eval(e, i)
+		if r == 0 {
+			return 0
+		}
+		if math.IsNaN(l) || math.IsNaN(r) {
+			return math.NaN()
+		}
+		return 1
+	case "or":
+		if l == 1 {
+			return 1
+		}
+		r := n.r.eval(e, i)
+		if r == 1 {
+			return 1
+		}
+		if math.IsNaN(l) || math.IsNaN(r) {
+			return math.NaN()
+		}
+		return 0
+	}
+
+	r := n.r.eval(e, i)
+	if math.IsNaN(l) || math.IsNaN(r) {
+		return math.NaN()
+	}
+
+	switch n.op {
+	case "+":
+		return l + r
+	case "-":
+		return l - r
+	case "*":
+		return l * r
+	case "/":
+		if r == 0 {
+			return math.NaN()
+		}
+		return l / r
+	case ">":
+		return boolValue(l > r)
+	case ">=":
+		return boolValue(l >= r)
+	case "<":
+		return boolValue(l < r)
+	case "<=":
+		return boolValue(l <= r)
+	case "==":
+		return boolValue(l == r)
+	case "!=":
+		return boolValue(l != r)
+	}
+	return math.NaN()
+}
+
+func (n *binaryNode) typ() valueType {
+	switch n.op {
+	case "+", "-", "*", "/":
+		return numberType
+	}
+	return boolType
+}
+
+func (n *binaryNode) span() int {
+	return max(n.l.span(), n.r.span())
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// callNode is a function call. Its whole series is computed once per
+// evaluation and then indexed.
+type callNode struct {
+	fn     *function
+	source node // numeric source, close when omitted
+	period int
+	colour types.CandleColor
+}
+
+func (n *callNode) eval(e *env, i int) float64 {
+	if !e.inRange(i) {
+		return math.NaN()
+	}
+	s, ok := e.series[n]
+	if !ok {
+		s = n.fn.compute(n, e)
+		e.series[n] = s
+	}
+	return s[i]
+}
+
+func (n *callNode) typ() valueType { return numberType }
+func (n *callNode) span() int      { return n.fn.span(n) }
+
+// sourceValues evaluates the call's source at every candle
+func (n *callNode) sourceValues(e *env) []float64 {
+	values := make([]float64, len(e.candles))
+	for i := range values {
+		values[i] = n.source.eval(e, i)
+	}
+	return values
+}
@@ -0,0 +1,151 @@
+package rules
+
+import (
+	"math"
+
+	"github.com/letieu/trade-bot/internal/indicators"
+)
+
+// warmupPeriods is how many periods recursive indicators (EMA, RSI, ATR) are
+// given to settle before their values are trusted
+const warmupPeriods = 5
+
+// maxRun caps how far back consecutive() looks
+const maxRun = 50
+
+// function describes the arguments a function takes and how to compute it
+type function struct {
+	source  bool // takes a numeric source first, defaulting to close
+	period  bool // takes a period as its last argument
+	colour  bool // takes a single colour
+	span    func(n *callNode) int
+	compute func(n *callNode, e *env) []float64
+}
+
+var functions = map[string]*function{
+	"sma": {
+		source: true, period: true,
+		span:    windowSpan,
+		compute: streaming(func(period int) adder { return indicators.NewSMA(period) }),
+	},
+	"ema": {
+		source: true, period: true,
+		span:    warmupSpan,
+		compute: streaming(func(period int) adder { return indicators.NewEMA(period) }),
+	},
+	"rsi": {
+		source: true, period: true,
+		span:    warmupSpan,
+		compute: streaming(func(period int) adder { return indicators.NewRSI(period) }),
+	},
+	"atr": {
+		period:  true,
+		span:    func(n *callNode) int { return warmupPeriods*n.period + 1 },
+		compute: atrSeries,
+	},
+	"highest": {
+		source: true, period: true,
+		span:    windowSpan,
+		compute: extreme(math.Max),
+	},
+	"lowest": {
+		source: true, period: true,
+		span:    windowSpan,
+		compute: extreme(math.Min),
+	},
+	"abs": {
+		source:  true,
+		span:    func(n *callNode) int { return n.source.span() },
+		compute: absSeries,
+	},
+	"consecutive": {
+		colour:  true,
+		span:    func(n *callNode) int { return maxRun },
+		compute: consecutiveSeries,
+	},
+}
+
+func windowSpan(n *callNode) int { return n.source.span() + n.period - 1 }
+func warmupSpan(n *callNode) int { return n.source.span() + warmupPeriods*n.period }
+
+// adder is a streaming indicator fed plain values
+type adder interface {
+	Add(v float64) float64
+	Ready() bool
+	Value() float64
+}
+
+// streaming feeds the source through a fresh indicator, skipping unknown values
+func streaming(newIndicator func(period int) adder) func(n *callNode, e *env) []float64 {
+	return func(n *callNode, e *env) []float64 {
+		ind := newIndicator(n.period)
+		out := n.sourceValues(e)
+		for i, v := range out {
+			out[i] = math.NaN()
+			if math.IsNaN(v) {
+				continue
+			}
+			ind.Add(v)
+			if ind.Ready() {
+				out[i] = ind.Value()
+			}
+		}
+		return out
+	}
+}
+
+func atrSeries(n *callNode, e *env) []float64 {
+	atr := indicators.NewATR(n.period)
+	out := make([]float64, len(e.candles))
+	for i, c := range e.candles {
+		atr.Update(c)
+		out[i] = math.NaN()
+		if atr.Ready() {
+			out[i] = atr.Value()
+		}
+	}
+	return out
+}
+
+// extreme returns the highest or lowest source value over the last period candles
+func extreme(pick func(a, b float64) float64) func(n *callNode, e *env) []float64 {
+	return func(n *callNode, e *env) []float64 {
+		values := n.sourceValues(e)
+		out := make([]float64, len(values))
+		for i := range values {
+			out[i] = math.NaN()
+			if i+1 < n.period {
+				continue
+			}
+			best := values[i]
+			for _, v := range values[i+1-n.period : i] {
+				best = pick(best, v)
+			}
+			out[i] = best // NaN if any value was unknown
+		}
+		return out
+	}
+}
+
+func absSeries(n *callNode, e *env) []float64 {
+	values := n.sourceValues(e)
+	for i, v := range values {
+		values[i] = math.Abs(v)
+	}
+	return values
+}
+
+// consecutiveSeries counts the candles in a row, up to maxRun, with the given colour
+func consecutiveSeries(n *callNode, e *env) []float64 {
+	out := make([]float64, len(e.candles))
+	run := 0
+	for i, c := range e.candles {
+		if c.Color() == n.colour {
+			run = min(run+1, maxRun)
+		} else {
+			run = 0
+		}
+		out[i] = float64(run)
+	}
+	return out
+}
@@ -0,0 +1,120 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokKeyword // and, or, not
+	tokOperator
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // byte offset in the source
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of rule"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+var keywords = map[string]bool{"and": true, "or": true, "not": true}
+
+// ParseError reports a problem with a rule and the token that caused it
+type ParseError struct {
+	Source string
+	Pos    int // byte offset of the offending token
+	Msg    string
+}
+
+// Error names the column and underlines it in the source, e.g.
+//
+//	column 8: unknown function "rsx"
+//		close > rsx(14)
+//		        ^
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("column %d: %s\n\t%s\n\t%s^", e.Pos+1, e.Msg, e.Source, strings.Repeat(" ", e.Pos))
+}
+
+func lex(source string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(source) {
+		c := source[i]
+		start := i
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case isDigit(c) || (c == '.' && i+1 < len(source) && isDigit(source[i+1])):
+			for i < len(source) && (isDigit(source[i]) || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: source[start:i], pos: start})
+		case isLetter(c):
+			for i < len(source) && (isLetter(source[i]) || isDigit(source[i])) {
+				i++
+			}
+			word := source[start:i]
+			kind := tokIdent
+			if keywords[word] {
+				kind = tokKeyword
+			}
+			tokens = append(tokens, token{kind: kind, text: word, pos: start})
+		default:
+			kind, width := punctuation(source[i:])
+			if width == 0 {
+				return nil, &ParseError{Source: source, Pos: start, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+			i += width
+			tokens = append(tokens, token{kind: kind, text: source[start:i], pos: start})
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, pos: len(source)}), nil
+}
+
+// punctuation returns the kind and length of the operator or bracket at the start of s
+func punctuation(s string) (tokenKind, int) {
+	if len(s) >= 2 {
+		switch s[:2] {
+		case ">=", "<=", "==", "!=":
+			return tokOperator, 2
+		}
+	}
+
+	switch s[0] {
+	case '>', '<', '+', '-', '*', '/':
+		return tokOperator, 1
+	case '(':
+		return tokLParen, 1
+	case ')':
+		return tokRParen, 1
+	case '[':
+		return tokLBracket, 1
+	case ']':
+		return tokRBracket, 1
+	case ',':
+		return tokComma, 1
+	}
+	return tokEOF, 0
+}
+
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+func isLetter(c byte) bool { return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
@@ -0,0 +1,399 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Grammar, loosest binding first:
+//
+//	or      = and { "or" and }
+//	and     = not { "and" not }
+//	not     = "not" not | compare
+//	compare = sum [ ( ">" | ">=" | "<" | "<=" | "==" | "!=" ) sum ]
+//	sum     = product { ( "+" | "-" ) product }
+//	product = unary { ( "*" | "/" ) unary }
+//	unary   = "-" unary | postfix
+//	postfix = primary [ "[" integer "]" ]
+//	primary = number | field | colour | name "(" args ")" | "(" or ")"
+//
+// Types are checked while parsing, so every error points at a token.
+
+type parser struct {
+	source string
+	tokens []token
+	pos    int
+}
+
+// parse compiles source into a condition node
+func parse(source string) (node, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{source: source, tokens: tokens}
+	start := p.peek()
+	if start.kind == tokEOF {
+		return nil, p.errorf(start, "empty rule")
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokEOF {
+		return nil, p.errorf(next, "unexpected %s after the end of the rule", next)
+	}
+	if root.typ() != boolType {
+		return nil, p.errorf(start, "rule must be a condition, not %s", root.typ())
+	}
+	return root, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(at token, format string, args ...interface{}) error {
+	return &ParseError{Source: p.source, Pos: at.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) expect(kind tokenKind, want string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, p.errorf(t, "expected %s, found %s", want, t)
+	}
+	return t, nil
+}
+
+// operand parses one side of an operator and checks its type
+func (p *parser) operand(parse func() (node, error), want valueType, op token) (node, error) {
+	at := p.peek()
+	n, err := parse()
+	if err != nil {
+		return nil, err
+	}
+	if n.typ() != want {
+		return nil, p.errorf(at, "%q needs %s, found %s", op.text, want, n.typ())
+	}
+	return n, nil
+}
+
+func (p *parser) parseLogical(keyword string, parse func() (node, error)) (node, error) {
+	at := p.peek()
+	left, err := parse()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokKeyword && p.peek().text == keyword {
+		op := p.next()
+		if left.typ() != boolType {
+			return nil, p.errorf(at, "%q needs %s, found %s", op.text, boolType, left.typ())
+		}
+		right, err := p.operand(parse, boolType, op)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op.text, l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	return p.parseLogical("or", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseLogical("and", p.parseNot)
+}
+
+func (p *parser) parseNot() (node, error) {
+	if t := p.peek(); t.kind == tokKeyword && t.text == "not" {
+		op := p.next()
+		x, err := p.operand(p.parseNot, boolType, op)
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "not", x: x}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	at := p.peek()
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	op := p.peek()
+	if op.kind != tokOperator || !isComparison(op.text) {
+		return left, nil
+	}
+	p.next()
+
+	if left.typ() != numberType {
+		return nil, p.errorf(at, "%q needs %s, found %s", op.text, numberType, left.typ())
+	}
+	right, err := p.operand(p.parseSum, numberType, op)
+	if err != nil {
+		return nil, err
+	}
+
+	if next := p.peek(); next.kind == tokOperator && isComparison(next.text) {
+		return nil, p.errorf(next, "comparisons cannot be chained, use \"and\"")
+	}
+	return &binaryNode{op: op.text, l: left, r: right}, nil
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case ">", ">=", "<", "<=", "==", "!=":
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseArithmetic(ops string, parse func() (node, error)) (node, error) {
+	at := p.peek()
+	left, err := parse()
+	if err != nil {
+		return nil, err
+	}
+
+	for t := p.peek(); t.kind == tokOperator && strings.Contains(ops, t.text); t = p.peek() {
+		op := p.next()
+		if left.typ() != numberType {
+			return nil, p.errorf(at, "%q needs %s, found %s", op.text, numberType, left.typ())
+		}
+		right, err := p.operand(parse, numberType, op)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op.text, l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	return p.parseArithmetic("+-", p.parseProduct)
+}
+
+func (p *parser) parseProduct() (node, error) {
+	return p.parseArithmetic("*/", p.parseUnary)
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if t := p.peek(); t.kind == tokOperator && t.text == "-" {
+		op := p.next()
+		x, err := p.operand(p.parseUnary, numberType, op)
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "-", x: x}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	at := p.peek()
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokLBracket {
+		return x, nil
+	}
+
+	p.next()
+	if x.typ() == colourType {
+		return nil, p.errorf(at, "a colour cannot be offset")
+	}
+	offsetTok := p.peek()
+	offset, err := p.parseInteger(0)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(tokRBracket, `"]"`); err != nil {
+		return nil, err
+	}
+	if offset == 0 {
+		return nil, p.errorf(offsetTok, "offset must be at least 1")
+	}
+	return &offsetNode{x: x, offset: offset}, nil
+}
+
+// parseInteger reads a whole number literal of at least min
+func (p *parser) parseInteger(min int) (int, error) {
+	t := p.next()
+	if t.kind != tokNumber {
+		return 0, p.errorf(t, "expected a whole number, found %s", t)
+	}
+	n, err := strconv.Atoi(t.text)
+	if err != nil || n < min {
+		return 0, p.errorf(t, "expected a whole number of at least %d, found %s", min, t)
+	}
+	return n, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		v, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, p.errorf(t, "invalid number %s", t)
+		}
+		return &numberNode{value: v}, nil
+
+	case tokLParen:
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, `")"`); err != nil {
+			return nil, err
+		}
+		return x, nil
+
+	case tokIdent:
+		name := strings.ToLower(t.text)
+		if p.peek().kind == tokLParen {
+			return p.parseCall(t, name)
+		}
+		if get, ok := fields[name]; ok {
+			return &fieldNode{get: get}, nil
+		}
+		if colour, ok := colours[name]; ok {
+			return &colourNode{colour: colour}, nil
+		}
+		if _, ok := functions[name]; ok {
+			return nil, p.errorf(t, "%s is a function, call it as %s(...)", name, name)
+		}
+		return nil, p.errorf(t, "unknown name %s (fields: %s)", t, strings.Join(sortedKeys(fields), ", "))
+	}
+
+	if t.kind == tokEOF {
+		return nil, p.errorf(t, "unexpected end of rule, expected a value")
+	}
+	return nil, p.errorf(t, "unexpected %s, expected a value", t)
+}
+
+// argument is a parsed call argument and the token it starts at
+type argument struct {
+	node node
+	at   token
+}
+
+func (p *parser) parseCall(nameTok token, name string) (node, error) {
+	fn, ok := functions[name]
+	if !ok {
+		return nil, p.errorf(nameTok, "unknown function %s (functions: %s)", nameTok, strings.Join(sortedKeys(functions), ", "))
+	}
+	p.next() // "("
+
+	var args []argument
+	if p.peek().kind != tokRParen {
+		for {
+			at := p.peek()
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, argument{node: arg, at: at})
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+	closing, err := p.expect(tokRParen, `"," or ")"`)
+	if err != nil {
+		return nil, err
+	}
+
+	call := &callNode{fn: fn}
+	switch {
+	case fn.colour:
+		if len(args) != 1 || args[0].node.typ() != colourType {
+			return nil, p.errorf(nameTok, "%s takes one colour: red or green", name)
+		}
+		call.colour = args[0].node.(*colourNode).colour
+		return call, nil
+
+	case fn.source && fn.period:
+		// f(period) reads close, f(source, period) reads source
+		if len(args) < 1 || len(args) > 2 {
+			return nil, p.errorf(nameTok, "%s takes (period) or (source, period)", name)
+		}
+		call.source = &fieldNode{get: fields["close"]}
+		if len(args) == 2 {
+			if err := p.checkArg(args[0], numberType); err != nil {
+				return nil, err
+			}
+			call.source = args[0].node
+		}
+		period, err := p.period(args[len(args)-1])
+		if err != nil {
+			return nil, err
+		}
+		call.period = period
+
+	case fn.period:
+		if len(args) != 1 {
+			return nil, p.errorf(nameTok, "%s takes (period)", name)
+		}
+		period, err := p.period(args[0])
+		if err != nil {
+			return nil, err
+		}
+		call.period = period
+
+	case fn.source:
+		if len(args) != 1 {
+			return nil, p.errorf(closing, "%s takes one argument", name)
+		}
+		if err := p.checkArg(args[0], numberType); err != nil {
+			return nil, err
+		}
+		call.source = args[0].node
+	}
+	return call, nil
+}
+
+func (p *parser) checkArg(arg argument, want valueType) error {
+	if arg.node.typ() != want {
+		return p.errorf(arg.at, "expected %s, found %s", want, arg.node.typ())
+	}
+	return nil
+}
+
+// period checks that a period argument is a positive whole number literal
+func (p *parser) period(arg argument) (int, error) {
+	n, ok := arg.node.(*numberNode)
+	if !ok || n.value != float64(int(n.value)) || n.value < 1 {
+		return 0, p.errorf(arg.at, "period must be a whole number of at least 1")
+	}
+	return int(n.value), nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
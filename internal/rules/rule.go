@@ -0,0 +1,81 @@
+// Package rules compiles trading rules written as text, such as
+//
+//	consecutive(red) >= 4 and rsi(14) < 30 and volume > 2 * sma(volume, 20)
+//
+// into PatternMatchers. A rule is a condition over the candles evaluated at
+// the last one: fields (open, high, low, close, volume, body, range), numbers,
+// arithmetic, comparisons, and/or/not, offsets into the past (close[1]) and
+// the functions sma, ema, rsi, atr, highest, lowest, abs and consecutive.
+package rules
+
+import (
+	"fmt"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// Rule is a compiled rule that implements types.PatternMatcher
+type Rule struct {
+	name      string
+	source    string
+	direction string
+	root      node
+}
+
+// Compile parses source into a rule reported under name. direction, if not
+// empty, is the direction of every match; otherwise the last candle's colour
+// decides. Errors are *ParseError and point at the offending token.
+func Compile(name, source, direction string) (*Rule, error) {
+	switch direction {
+	case "", "bullish", "bearish", "neutral":
+	default:
+		return nil, fmt.Errorf("direction must be bullish, bearish or neutral, got %q", direction)
+	}
+
+	root, err := parse(source)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = source
+	}
+	return &Rule{name: name, source: source, direction: direction, root: root}, nil
+}
+
+func (r *Rule) Match(candles []types.Candle) (*types.MatchResult, error) {
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("need at least 1 candle")
+	}
+	// Unknown (NaN) results never match
+	if r.root.eval(newEnv(candles), len(candles)-1) != 1 {
+		return nil, nil
+	}
+
+	last := candles[len(candles)-1]
+	direction := r.direction
+	if direction == "" {
+		direction = types.Bullish
+		if last.Color() == types.ColorRed {
+			direction = types.Bearish
+		}
+	}
+
+	return &types.MatchResult{
+		Direction:  direction,
+		Confidence: 1,
+		Candles:    candles[len(candles)-1:],
+		Metadata:   map[string]interface{}{"rule": r.source},
+	}, nil
+}
+
+func (r *Rule) GetName() string {
+	return r.name
+}
+
+func (r *Rule) GetDescription() string {
+	return r.source
+}
+
+func (r *Rule) GetRequiredCandles() int {
+	return r.root.span()
+}
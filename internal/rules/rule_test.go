@@ -0,0 +1,204 @@
+package rules
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/indicators"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// candles builds candles from closes, each opening at the previous close
+func candles(closes ...float64) []types.Candle {
+	out := make([]types.Candle, len(closes))
+	prev := closes[0]
+	for i, c := range closes {
+		out[i] = types.Candle{
+			Timestamp: int64(i),
+			Open:      prev,
+			High:      max(prev, c) + 1,
+			Low:       min(prev, c) - 1,
+			Close:     c,
+			Volume:    100,
+		}
+		prev = c
+	}
+	return out
+}
+
+func TestRule_Match(t *testing.T) {
+	// Four red candles after a flat start, then a volume spike on the last one
+	data := candles(100, 100, 99, 98, 97, 96)
+	data[len(data)-1].Volume = 500
+
+	tests := []struct {
+		rule string
+		want bool
+	}{
+		{"consecutive(red) >= 4", true},
+		{"consecutive(red) >= 5", false},
+		{"consecutive(green) == 0", true},
+		{"close < open", true},
+		{"close[1] == 97", true},
+		{"close - close[4] == -4", true},
+		{"volume > 2 * sma(volume, 5)", true},
+		{"volume > 2 * sma(volume, 5)[1]", true},
+		{"highest(high, 3) == 100", true},
+		{"lowest(close, 6) == 96", true},
+		{"abs(close - open) == body", true},
+		{"range == 3", true},
+		{"-close < 0 and not close > 100", true},
+		{"close > 1000 or (close < 1000 and volume >= 500)", true},
+		{"2 + 3 * 4 == 14", true},
+		{"(2 + 3) * 4 == 20", true},
+		{"close / 0 > 1", false},     // division by zero is unknown
+		{"not close / 0 > 1", false}, // and so is its negation
+		{"close[10] > 0", false},     // before the first candle
+		{"sma(10) > 0", false},       // still warming up
+		{"close[10] > 0 or close > 0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := Compile("", tt.rule, "")
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			got, err := rule.Match(data)
+			if err != nil {
+				t.Fatalf("Match() error = %v", err)
+			}
+			if (got != nil) != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRule_Indicators(t *testing.T) {
+	closes := make([]float64, 120)
+	for i := range closes {
+		closes[i] = 100 + float64(i%7) - float64(i%5)
+	}
+	data := candles(closes...)
+	last := len(data) - 1
+
+	rsi := indicators.RSISeries(data, 14)[last]
+	ema := indicators.EMASeries(data, 20)[last]
+	atr := indicators.ATRSeries(data, 14)[last]
+
+	env := newEnv(data)
+	for source, want := range map[string]float64{"rsi(14)": rsi, "ema(close, 20)": ema, "atr(14)": atr} {
+		root, err := parse(source + " == 0")
+		if err != nil {
+			t.Fatalf("%s: %v", source, err)
+		}
+		if got := root.(*binaryNode).l.eval(env, last); got != want {
+			t.Errorf("%s = %v, want %v", source, got, want)
+		}
+	}
+}
+
+func TestRule_RequiredCandles(t *testing.T) {
+	tests := []struct {
+		rule string
+		want int
+	}{
+		{"close > open", 1},
+		{"close[3] > open", 4},
+		{"close > sma(20)", 20},
+		{"close > sma(volume[1], 20)", 21},
+		{"rsi(14) < 30", 71},
+		{"atr(14) > 1", 71},
+		{"consecutive(red) >= 4", maxRun},
+	}
+
+	for _, tt := range tests {
+		rule, err := Compile("", tt.rule, "")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.rule, err)
+		}
+		if got := rule.GetRequiredCandles(); got != tt.want {
+			t.Errorf("%s: GetRequiredCandles() = %d, want %d", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestRule_Metadata(t *testing.T) {
+	rule, err := Compile("OVERSOLD", "rsi(14) < 30", "bullish")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.GetName() != "OVERSOLD" || rule.GetDescription() != "rsi(14) < 30" {
+		t.Errorf("name/description = %q/%q", rule.GetName(), rule.GetDescription())
+	}
+	// A steady decline takes RSI to 0
+	falling := make([]float64, 80)
+	for i := range falling {
+		falling[i] = 1000 - float64(i)*10
+	}
+	match, err := rule.Match(candles(falling...))
+	if err != nil || match == nil {
+		t.Fatalf("Match() = %v, %v; want a match", match, err)
+	}
+	if match.Direction != "bullish" || match.Metadata["rule"] != "rsi(14) < 30" {
+		t.Errorf("match = %+v, want bullish with the rule in the metadata", match)
+	}
+
+	if _, err := Compile("", "close > 1", "up"); err == nil {
+		t.Error("expected an error for an unknown direction")
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		rule    string
+		wantPos int
+		wantMsg string
+	}{
+		{"", 0, "empty rule"},
+		{"close >", 7, "unexpected end of rule"},
+		{"close > rsx(14)", 8, `unknown function "rsx"`},
+		{"clsoe > 1", 0, `unknown name "clsoe"`},
+		{"rsi(14) < 30 and", 16, "unexpected end of rule"},
+		{"rsi(14 < 30", 11, `expected "," or ")", found end of rule`},
+		{"close > 1 close", 10, `unexpected "close"`},
+		{"close $ 1", 6, "unexpected character"},
+		{"close + 1", 0, "must be a condition"},
+		{"close > 1 and volume", 14, `"and" needs a condition, found a number`},
+		{"sma(volume, 2.5) > 1", 12, "period must be a whole number"},
+		{"sma(volume, close) > 1", 12, "period must be a whole number"},
+		{"consecutive(3) > 1", 0, "takes one colour"},
+		{"1 < close < 2", 10, "cannot be chained"},
+		{"close[0] > 1", 6, "offset must be at least 1"},
+		{"red > 1", 0, `">" needs a number, found a colour`},
+		{"sma > 1", 0, "is a function"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			_, err := Compile("", tt.rule, "")
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Compile() error = %v, want a *ParseError", err)
+			}
+			if parseErr.Pos != tt.wantPos {
+				t.Errorf("Pos = %d, want %d (%v)", parseErr.Pos, tt.wantPos, err)
+			}
+			if !strings.Contains(parseErr.Msg, tt.wantMsg) {
+				t.Errorf("Msg = %q, want it to contain %q", parseErr.Msg, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestParseError_PointsAtToken(t *testing.T) {
+	_, err := Compile("", "close > rsx(14)", "")
+	want := "column 9: unknown function \"rsx\" (functions: abs, atr, consecutive, ema, highest, lowest, rsi, sma)\n" +
+		"\tclose > rsx(14)\n" +
+		"\t        ^"
+	if err == nil || err.Error() != want {
+		t.Errorf("Error() =\n%v\nwant\n%s", err, want)
+	}
+}
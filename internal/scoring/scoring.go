@@ -0,0 +1,198 @@
+// Package scoring rates signals so the strongest can be shown first.
+//
+// Each component ranges from 0 to 1 and the total is their weighted average
+// scaled to 0-100:
+//   - pattern: the strategy's match confidence
+//   - volume: the signal candle's volume against the average of the ones before
+//   - rsi: how stretched RSI is in the signal's favour (oversold for bullish),
+//     or 0.5 before RSI is ready
+//   - trend: agreement with the close against an EMA on the next higher timeframe
+//   - liquidity: average quote turnover per candle, on a log scale
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/indicators"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+const (
+	volumePeriod = 20 // candles averaged for the volume and liquidity components
+	volumeFull   = 3  // a volume this many times the average scores 1
+	rsiFull      = 30 // RSI this far from 50 in the signal's favour scores 1
+)
+
+// Scorer rates signals with configured weights
+type Scorer struct {
+	config config.ScoringConfig
+}
+
+// NewScorer fills unset settings with their defaults. Weights that are all
+// zero mean the defaults; a negative weight is an error.
+func NewScorer(cfg config.ScoringConfig) (*Scorer, error) {
+	defaults := config.DefaultScoring()
+	w := cfg.Weights
+	for _, weight := range []struct {
+		name  string
+		value float64
+	}{{"pattern", w.Pattern}, {"volume", w.Volume}, {"rsi", w.RSI}, {"trend", w.Trend}, {"liquidity", w.Liquidity}} {
+		if weight.value < 0 {
+			return nil, fmt.Errorf("invalid scoring config: weight %s must not be negative, got %g", weight.name, weight.value)
+		}
+	}
+	if w.Pattern+w.Volume+w.RSI+w.Trend+w.Liquidity == 0 {
+		cfg.Weights = defaults.Weights
+	}
+	if cfg.TrendPeriod <= 0 {
+		cfg.TrendPeriod = defaults.TrendPeriod
+	}
+	if cfg.LiquidityLow <= 0 {
+		cfg.LiquidityLow = defaults.LiquidityLow
+	}
+	if cfg.LiquidityHigh <= cfg.LiquidityLow {
+		cfg.LiquidityHigh = cfg.LiquidityLow * 1000
+	}
+	return &Scorer{config: cfg}, nil
+}
+
+// TrendPeriod is the EMA period used on the higher timeframe
+func (s *Scorer) TrendPeriod() int {
+	return s.config.TrendPeriod
+}
+
+// HigherTimeframe reports whether the trend component should be fetched
+func (s *Scorer) HigherTimeframe() bool {
+	return s.config.HigherTimeframe
+}
+
+// EMATrend is the direction of the last close against its EMA, or "" when
+// there are not enough candles
+func EMATrend(candles []types.Candle, period int) string {
+	ema := indicators.NewEMA(period)
+	for _, c := range candles {
+		ema.Update(c)
+	}
+	if !ema.Ready() {
+		return ""
+	}
+
+	last := candles[len(candles)-1].Close
+	switch {
+	case last > ema.Value():
+		return types.Bullish
+	case last < ema.Value():
+		return types.Bearish
+	}
+	return types.Neutral
+}
+
+// Score sets signal.Score. candles end at the signal candle; higherTrend is
+// the direction on the higher timeframe, or "" when unknown.
+func (s *Scorer) Score(signal *types.Signal, candles []types.Candle, higherTrend string) {
+	score := types.SignalScore{
+		Pattern:   clamp(signal.Confidence),
+		Volume:    volumeScore(candles),
+		RSI:       rsiScore(signal.Trend, signal.RSI),
+		Trend:     trendScore(signal.Trend, higherTrend),
+		Liquidity: s.liquidityScore(candles),
+	}
+
+	w := s.config.Weights
+	weighted := w.Pattern*score.Pattern + w.Volume*score.Volume + w.RSI*score.RSI +
+		w.Trend*score.Trend + w.Liquidity*score.Liquidity
+	score.Total = 100 * weighted / (w.Pattern + w.Volume + w.RSI + w.Trend + w.Liquidity)
+
+	signal.Score = score
+}
+
+func volumeScore(candles []types.Candle) float64 {
+	if len(candles) < 2 {
+		return 0
+	}
+	last := candles[len(candles)-1]
+	before := candles[max(0, len(candles)-1-volumePeriod) : len(candles)-1]
+
+	total := 0.0
+	for _, c := range before {
+		total += c.Volume
+	}
+	if total == 0 {
+		return 0
+	}
+	ratio := last.Volume / (total / float64(len(before)))
+	return clamp((ratio - 1) / (volumeFull - 1))
+}
+
+func rsiScore(trend string, rsi float64) float64 {
+	// RSI is left at 0 until it has enough candles, which says nothing either way
+	if rsi == 0 {
+		return 0.5
+	}
+	switch trend {
+	case types.Bullish:
+		return clamp((50 - rsi) / rsiFull)
+	case types.Bearish:
+		return clamp((rsi - 50) / rsiFull)
+	}
+	return clamp(math.Abs(rsi-50) / rsiFull)
+}
+
+func trendScore(trend, higherTrend string) float64 {
+	if higherTrend == "" || higherTrend == types.Neutral || trend == types.Neutral {
+		return 0.5
+	}
+	if trend == higherTrend {
+		return 1
+	}
+	return 0
+}
+
+func (s *Scorer) liquidityScore(candles []types.Candle) float64 {
+	recent := candles[max(0, len(candles)-volumePeriod):]
+	if len(recent) == 0 {
+		return 0
+	}
+
+	turnover := 0.0
+	for _, c := range recent {
+		turnover += c.Close * c.Volume
+	}
+	turnover /= float64(len(recent))
+	if turnover <= s.config.LiquidityLow {
+		return 0
+	}
+	return clamp(math.Log(turnover/s.config.LiquidityLow) / math.Log(s.config.LiquidityHigh/s.config.LiquidityLow))
+}
+
+func clamp(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return math.Max(0, math.Min(1, v))
+}
+
+// Rank sorts signals by score, highest first, keeping the existing order for ties
+func Rank(signals []types.Signal) {
+	sort.SliceStable(signals, func(i, j int) bool {
+		return signals[i].Score.Total > signals[j].Score.Total
+	})
+}
+
+// Filter drops signals scoring below minScore
+func Filter(signals []types.Signal, minScore float64) []types.Signal {
+	if minScore <= 0 {
+		return signals
+	}
+
+	kept := signals[:0:0]
+	for _, signal := range signals {
+		if signal.Score.Total >= minScore {
+			kept = append(kept, signal)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,145 @@
+package scoring
+
+import (
+	"math"
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// flat returns n candles closing at price with the given volume
+func flat(n int, price, volume float64) []types.Candle {
+	candles := make([]types.Candle, n)
+	for i := range candles {
+		candles[i] = types.Candle{Open: price, High: price + 1, Low: price - 1, Close: price, Volume: volume}
+	}
+	return candles
+}
+
+func TestScorer_Components(t *testing.T) {
+	scorer, err := NewScorer(config.ScoringConfig{})
+	if err != nil {
+		t.Fatalf("NewScorer() error = %v", err)
+	}
+
+	// Volume doubles on the signal candle: ratio 2 scores (2-1)/(3-1)
+	candles := flat(21, 100, 1000)
+	candles[20].Volume = 2000
+
+	signal := types.Signal{Trend: types.Bullish, Confidence: 0.8, RSI: 35}
+	scorer.Score(&signal, candles, types.Bearish)
+
+	want := types.SignalScore{
+		Pattern: 0.8,
+		Volume:  0.5,
+		RSI:     0.5, // 15 below 50 out of 30
+		Trend:   0,   // against the higher timeframe
+		// Turnover of ~105k per candle is barely above the 100k floor
+		Liquidity: math.Log(105000.0/1e5) / math.Log(1000),
+	}
+	got := signal.Score
+	for name, pair := range map[string][2]float64{
+		"pattern":   {got.Pattern, want.Pattern},
+		"volume":    {got.Volume, want.Volume},
+		"rsi":       {got.RSI, want.RSI},
+		"trend":     {got.Trend, want.Trend},
+		"liquidity": {got.Liquidity, want.Liquidity},
+	} {
+		if math.Abs(pair[0]-pair[1]) > 1e-9 {
+			t.Errorf("%s = %v, want %v", name, pair[0], pair[1])
+		}
+	}
+
+	w := config.DefaultScoring().Weights
+	total := 100 * (w.Pattern*0.8 + w.Volume*0.5 + w.RSI*0.5 + w.Liquidity*want.Liquidity) /
+		(w.Pattern + w.Volume + w.RSI + w.Trend + w.Liquidity)
+	if math.Abs(got.Total-total) > 1e-9 {
+		t.Errorf("Total = %v, want %v", got.Total, total)
+	}
+}
+
+func TestRSIAndTrendScores(t *testing.T) {
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"bullish oversold", rsiScore(types.Bullish, 20), 1},
+		{"bullish overbought", rsiScore(types.Bearish, 20), 0},
+		{"bearish overbought", rsiScore(types.Bearish, 80), 1},
+		{"neutral stretched", rsiScore(types.Neutral, 35), 0.5},
+		{"bullish before RSI is ready", rsiScore(types.Bullish, 0), 0.5},
+		{"bearish before RSI is ready", rsiScore(types.Bearish, 0), 0.5},
+		{"aligned", trendScore(types.Bullish, types.Bullish), 1},
+		{"opposed", trendScore(types.Bearish, types.Bullish), 0},
+		{"unknown", trendScore(types.Bullish, ""), 0.5},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestNewScorer_Weights(t *testing.T) {
+	if _, err := NewScorer(config.ScoringConfig{Weights: config.ScoreWeights{Pattern: 1, Volume: -0.5}}); err == nil {
+		t.Error("NewScorer() error = nil, want an error for a negative weight")
+	}
+
+	// A negative weight must not be hidden by falling back to the defaults
+	if _, err := NewScorer(config.ScoringConfig{Weights: config.ScoreWeights{Pattern: -1}}); err == nil {
+		t.Error("NewScorer() error = nil, want an error when all weights are negative")
+	}
+
+	scorer, err := NewScorer(config.ScoringConfig{Weights: config.ScoreWeights{Pattern: 1}})
+	if err != nil {
+		t.Fatalf("NewScorer() error = %v", err)
+	}
+	signal := types.Signal{Trend: types.Bullish, Confidence: 0.6}
+	scorer.Score(&signal, flat(21, 100, 1000), "")
+	if math.Abs(signal.Score.Total-60) > 1e-9 {
+		t.Errorf("Total = %v, want 60 from the pattern alone", signal.Score.Total)
+	}
+}
+
+func TestEMATrend(t *testing.T) {
+	candles := flat(10, 100, 1)
+	if got := EMATrend(candles[:4], 5); got != "" {
+		t.Errorf("EMATrend() = %q before the EMA is ready, want \"\"", got)
+	}
+
+	candles[9].Close = 110
+	if got := EMATrend(candles, 5); got != types.Bullish {
+		t.Errorf("EMATrend() = %q, want bullish", got)
+	}
+	candles[9].Close = 90
+	if got := EMATrend(candles, 5); got != types.Bearish {
+		t.Errorf("EMATrend() = %q, want bearish", got)
+	}
+}
+
+func TestRankAndFilter(t *testing.T) {
+	signals := []types.Signal{
+		{Symbol: "A", Score: types.SignalScore{Total: 40}},
+		{Symbol: "B", Score: types.SignalScore{Total: 90}},
+		{Symbol: "C", Score: types.SignalScore{Total: 40}},
+		{Symbol: "D", Score: types.SignalScore{Total: 70}},
+	}
+
+	Rank(signals)
+	var order string
+	for _, s := range signals {
+		order += s.Symbol
+	}
+	if order != "BDAC" {
+		t.Errorf("Rank() order = %s, want BDAC", order)
+	}
+
+	if kept := Filter(signals, 50); len(kept) != 2 {
+		t.Errorf("Filter() kept %d signals, want 2", len(kept))
+	}
+	if kept := Filter(signals, 0); len(kept) != 4 {
+		t.Errorf("Filter() with no minimum kept %d signals, want 4", len(kept))
+	}
+}
@@ -0,0 +1,115 @@
+package strategies
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/letieu/trade-bot/internal/indicators"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// Breakout flags a close beyond the Donchian channel, the highest high or
+// lowest low of the Lookback candles before the breakout
+type Breakout struct {
+	Lookback  int     // Candles forming the channel
+	Confirm   int     // Further closes beyond the level required after the breakout candle
+	ATRPeriod int     // ATR used to measure the breakout distance
+	MinATR    float64 // Minimum distance of the last close beyond the level in ATRs, 0 disables
+}
+
+func NewBreakout(lookback, confirm, atrPeriod int, minATR float64) *Breakout {
+	return &Breakout{Lookback: lookback, Confirm: confirm, ATRPeriod: atrPeriod, MinATR: minATR}
+}
+
+// Match reports a bullish breakout above the channel high or a bearish one
+// below the low. It fires once, on the candle completing the confirmation,
+// and only if the candle before the breakout had not itself broken out.
+// Confidence is the distance beyond the level in ATRs, capped at 1.
+func (s *Breakout) Match(candles []types.Candle) (*types.MatchResult, error) {
+	if len(candles) < s.GetRequiredCandles() {
+		return nil, fmt.Errorf("need at least %d candles, got %d", s.GetRequiredCandles(), len(candles))
+	}
+
+	// The breakout candle and its confirmations close the window
+	start := len(candles) - s.Confirm - 1
+	high, low := donchian(candles[start-s.Lookback : start])
+
+	run := candles[start:]
+	direction, level := "", 0.0
+	switch {
+	case allClose(run, func(c float64) bool { return c > high }):
+		direction, level = types.Bullish, high
+	case allClose(run, func(c float64) bool { return c < low }):
+		direction, level = types.Bearish, low
+	default:
+		return nil, nil
+	}
+
+	// The candle before broke out of its own channel the same way: not a fresh break
+	prevHigh, prevLow := donchian(candles[start-1-s.Lookback : start-1])
+	if prev := candles[start-1].Close; (direction == types.Bullish && prev > prevHigh) || (direction == types.Bearish && prev < prevLow) {
+		return nil, nil
+	}
+
+	last := run[len(run)-1]
+	distance := math.Abs(last.Close - level)
+	atrSeries := indicators.ATRSeries(candles, s.ATRPeriod)
+	atr := atrSeries[len(atrSeries)-1]
+
+	distanceATR := 0.0
+	if atr > 0 {
+		distanceATR = distance / atr
+	}
+	if s.MinATR > 0 && distanceATR < s.MinATR {
+		return nil, nil
+	}
+
+	return &types.MatchResult{
+		Direction:  direction,
+		Confidence: math.Min(1, distanceATR),
+		Candles:    run,
+		Metadata: map[string]interface{}{
+			"level":        level,
+			"lookback":     s.Lookback,
+			"atr_distance": distanceATR,
+		},
+	}, nil
+}
+
+// donchian returns the highest high and lowest low of candles
+func donchian(candles []types.Candle) (high, low float64) {
+	high, low = candles[0].High, candles[0].Low
+	for _, candle := range candles[1:] {
+		high = math.Max(high, candle.High)
+		low = math.Min(low, candle.Low)
+	}
+	return high, low
+}
+
+// allClose reports whether every candle's close satisfies beyond
+func allClose(candles []types.Candle, beyond func(float64) bool) bool {
+	for _, candle := range candles {
+		if !beyond(candle.Close) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Breakout) GetName() string {
+	return "PHÁ VỠ"
+}
+
+func (s *Breakout) GetDescription() string {
+	return fmt.Sprintf("Detects closes beyond the %d-candle high or low, confirmed by %d more candles", s.Lookback, s.Confirm)
+}
+
+// GetRequiredCandles covers the channels of the breakout and the candle
+// before it, the confirmations and the ATR warm-up
+func (s *Breakout) GetRequiredCandles() int {
+	history := s.Lookback + 2
+	if s.ATRPeriod > history {
+		history = s.ATRPeriod
+	}
+	return history + s.Confirm + 1
+}
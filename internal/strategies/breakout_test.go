@@ -0,0 +1,88 @@
+package strategies
+
+import (
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// ranging returns n candles trading between 99 and 101, followed by candles
+// closing at each of closes with a half point wick beyond the close
+func ranging(n int, closes ...float64) []types.Candle {
+	candles := make([]types.Candle, 0, n+len(closes))
+	for i := 0; i < n; i++ {
+		candles = append(candles, ohlc(100, 101, 99, 100))
+	}
+	for _, c := range closes {
+		candles = append(candles, ohlc(100, max(c, 100)+0.5, min(c, 100)-0.5, c))
+	}
+	return candles
+}
+
+func TestBreakout(t *testing.T) {
+	tests := []struct {
+		name          string
+		strategy      *Breakout
+		candles       []types.Candle
+		wantDirection string // "" means no match
+		wantLevel     float64
+		wantCandles   int
+	}{
+		{"close above the high", NewBreakout(5, 0, 14, 0), ranging(20, 103), types.Bullish, 101, 1},
+		{"close below the low", NewBreakout(5, 0, 14, 0), ranging(20, 97), types.Bearish, 99, 1},
+		{"close inside the channel", NewBreakout(5, 0, 14, 0), ranging(20, 100.8), "", 0, 0},
+		{"second close above", NewBreakout(5, 0, 14, 0), ranging(20, 103, 104), "", 0, 0},
+		{"confirmed", NewBreakout(5, 1, 14, 0), ranging(20, 103, 102), types.Bullish, 101, 2},
+		{"confirmation back inside", NewBreakout(5, 1, 14, 0), ranging(20, 103, 100.5), "", 0, 0},
+		{"far enough in ATRs", NewBreakout(5, 0, 14, 0.5), ranging(20, 103), types.Bullish, 101, 1},
+		{"too close in ATRs", NewBreakout(5, 0, 14, 2), ranging(20, 103), "", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := tt.strategy.Match(tt.candles)
+			if err != nil {
+				t.Fatalf("Match() error = %v", err)
+			}
+			if (match != nil) != (tt.wantDirection != "") {
+				t.Fatalf("Match() = %+v, want direction %q", match, tt.wantDirection)
+			}
+			if match == nil {
+				return
+			}
+
+			if match.Direction != tt.wantDirection {
+				t.Errorf("direction = %q, want %q", match.Direction, tt.wantDirection)
+			}
+			if match.Metadata["level"] != tt.wantLevel {
+				t.Errorf("level = %v, want %v", match.Metadata["level"], tt.wantLevel)
+			}
+			if len(match.Candles) != tt.wantCandles {
+				t.Errorf("pattern has %d candles, want %d", len(match.Candles), tt.wantCandles)
+			}
+			if match.Confidence <= 0 || match.Confidence > 1 {
+				t.Errorf("Confidence = %v, want (0, 1]", match.Confidence)
+			}
+		})
+	}
+}
+
+func TestBreakout_RequiredCandles(t *testing.T) {
+	tests := []struct {
+		strategy *Breakout
+		want     int
+	}{
+		{NewBreakout(20, 0, 14, 0), 23},
+		{NewBreakout(20, 2, 14, 0), 25},
+		{NewBreakout(5, 0, 14, 0), 15},
+	}
+	for _, tt := range tests {
+		if got := tt.strategy.GetRequiredCandles(); got != tt.want {
+			t.Errorf("%+v requires %d candles, want %d", tt.strategy, got, tt.want)
+		}
+	}
+
+	if _, err := NewBreakout(20, 0, 14, 0).Match(ranging(10, 103)); err == nil {
+		t.Error("expected an error without a full channel")
+	}
+}
@@ -0,0 +1,153 @@
+package strategies
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// CandleThresholds are the body and wick ratios used by the candlestick
+// patterns. Unless noted, ratios are fractions of the candle's high-low range.
+type CandleThresholds struct {
+	DojiBody  float64 // a body at most this large is a doji
+	SmallBody float64 // a body at most this large is small (stars, hammers, harami)
+	LongBody  float64 // a body at least this large is long
+	LongWick  float64 // a long wick is at least this many times the body
+	ShortWick float64 // a short wick is at most this large
+	PinNose   float64 // a pin bar's nose is at least this large
+	TrendBars int     // reversal patterns must follow an opposite move over this many candles; 0 disables
+}
+
+func DefaultCandleThresholds() CandleThresholds {
+	return CandleThresholds{
+		DojiBody:  0.1,
+		SmallBody: 0.3,
+		LongBody:  0.6,
+		LongWick:  2,
+		ShortWick: 0.1,
+		PinNose:   0.66,
+		TrendBars: 3,
+	}
+}
+
+// detectFunc inspects exactly the pattern's candles and returns the direction
+// of the match, or "" when the pattern is not present
+type detectFunc func(t CandleThresholds, pattern []types.Candle) (direction string, extra map[string]interface{})
+
+// CandlestickPattern is a PatternMatcher for one classic candlestick pattern.
+// Patterns with a bullish and a bearish form match either one and report
+// which as the match direction.
+type CandlestickPattern struct {
+	Thresholds CandleThresholds
+
+	name        string
+	description string
+	size        int  // candles in the pattern itself
+	reversal    bool // must follow a move against the pattern's direction
+	detect      detectFunc
+}
+
+func newCandlestickPattern(name, description string, size int, reversal bool, detect detectFunc) *CandlestickPattern {
+	return &CandlestickPattern{
+		Thresholds:  DefaultCandleThresholds(),
+		name:        name,
+		description: description,
+		size:        size,
+		reversal:    reversal,
+		detect:      detect,
+	}
+}
+
+// Match runs the detector on the last candles and applies the trend filter.
+// Confidence is how cleanly the prior candles trended into a reversal
+// pattern (net move over the sum of their ranges), or 1 when there is no
+// trend to check.
+func (p *CandlestickPattern) Match(candles []types.Candle) (*types.MatchResult, error) {
+	if required := p.GetRequiredCandles(); len(candles) < required {
+		return nil, fmt.Errorf("need at least %d candles, got %d", required, len(candles))
+	}
+
+	pattern := candles[len(candles)-p.size:]
+	direction, extra := p.detect(p.Thresholds, pattern)
+	if direction == "" {
+		return nil, nil
+	}
+
+	confidence := 1.0
+	if p.reversal && p.Thresholds.TrendBars > 0 {
+		before := candles[:len(candles)-p.size]
+		trend := before[len(before)-p.Thresholds.TrendBars:]
+		move := priorMove(trend)
+		if (direction == types.Bullish && move >= 0) || (direction == types.Bearish && move <= 0) {
+			return nil, nil
+		}
+		confidence = trendEfficiency(trend)
+	}
+
+	return &types.MatchResult{
+		Direction:  direction,
+		Confidence: confidence,
+		Candles:    pattern,
+		Metadata:   extra,
+	}, nil
+}
+
+func (p *CandlestickPattern) GetName() string {
+	return p.name
+}
+
+func (p *CandlestickPattern) GetDescription() string {
+	return p.description
+}
+
+func (p *CandlestickPattern) GetRequiredCandles() int {
+	if p.reversal {
+		return p.size + p.Thresholds.TrendBars
+	}
+	return p.size
+}
+
+// priorMove is the net price change over the candles
+func priorMove(candles []types.Candle) float64 {
+	return candles[len(candles)-1].Close - candles[0].Open
+}
+
+// trendEfficiency is the net move as a fraction of the total range travelled,
+// 1 for a straight line and near 0 for chop
+func trendEfficiency(candles []types.Candle) float64 {
+	travelled := 0.0
+	for _, c := range candles {
+		travelled += candleRange(c)
+	}
+	if travelled == 0 {
+		return 0
+	}
+	return math.Min(1, math.Abs(priorMove(candles))/travelled)
+}
+
+func body(c types.Candle) float64        { return math.Abs(c.Close - c.Open) }
+func candleRange(c types.Candle) float64 { return c.High - c.Low }
+func bodyTop(c types.Candle) float64     { return math.Max(c.Open, c.Close) }
+func bodyBottom(c types.Candle) float64  { return math.Min(c.Open, c.Close) }
+func bodyMid(c types.Candle) float64     { return (c.Open + c.Close) / 2 }
+func upperWick(c types.Candle) float64   { return c.High - bodyTop(c) }
+func lowerWick(c types.Candle) float64   { return bodyBottom(c) - c.Low }
+
+func isGreen(c types.Candle) bool { return c.Close > c.Open }
+func isRed(c types.Candle) bool   { return c.Close < c.Open }
+
+// bodyRatio is the body as a fraction of the range; a flat candle has none
+func bodyRatio(c types.Candle) float64 {
+	r := candleRange(c)
+	if r == 0 {
+		return 0
+	}
+	return body(c) / r
+}
+
+func (t CandleThresholds) isDoji(c types.Candle) bool { return bodyRatio(c) <= t.DojiBody }
+func (t CandleThresholds) isSmall(c types.Candle) bool {
+	return candleRange(c) > 0 && bodyRatio(c) <= t.SmallBody
+}
+func (t CandleThresholds) isLong(c types.Candle) bool { return bodyRatio(c) >= t.LongBody }
@@ -0,0 +1,271 @@
+package strategies
+
+import "github.com/letieu/trade-bot/internal/types"
+
+// CandlestickPatterns returns every candlestick pattern with default thresholds
+func CandlestickPatterns() []*CandlestickPattern {
+	return []*CandlestickPattern{
+		NewEngulfing(),
+		NewHammer(),
+		NewShootingStar(),
+		NewDoji(),
+		NewMorningStar(),
+		NewEveningStar(),
+		NewHarami(),
+		NewPiercingLine(),
+		NewDarkCloudCover(),
+		NewThreeWhiteSoldiers(),
+		NewThreeBlackCrows(),
+		NewInsideBar(),
+		NewPinBar(),
+	}
+}
+
+// NewEngulfing matches a candle whose body engulfs the previous opposite-colour body
+func NewEngulfing() *CandlestickPattern {
+	return newCandlestickPattern("NHẤN CHÌM",
+		"Bullish or bearish engulfing: a body that covers the previous opposite-colour body after a move the other way",
+		2, true, detectEngulfing)
+}
+
+func detectEngulfing(t CandleThresholds, p []types.Candle) (string, map[string]interface{}) {
+	prev, c := p[0], p[1]
+	if body(c) <= body(prev) {
+		return "", nil
+	}
+	switch {
+	case isRed(prev) && isGreen(c) && c.Open <= prev.Close && c.Close >= prev.Open:
+		return types.Bullish, nil
+	case isGreen(prev) && isRed(c) && c.Open >= prev.Close && c.Close <= prev.Open:
+		return types.Bearish, nil
+	}
+	return "", nil
+}
+
+// NewHammer matches a small body at the top of the range with a long lower wick after a decline
+func NewHammer() *CandlestickPattern {
+	return newCandlestickPattern("BÚA",
+		"Hammer: small body near the high with a long lower wick after a decline",
+		1, true, detectHammer)
+}
+
+func detectHammer(t CandleThresholds, p []types.Candle) (string, map[string]interface{}) {
+	c := p[0]
+	if t.isSmall(c) && lowerWick(c) >= t.LongWick*body(c) && upperWick(c) <= t.ShortWick*candleRange(c) {
+		return types.Bullish, nil
+	}
+	return "", nil
+}
+
+// NewShootingStar matches a small body at the bottom of the range with a long upper wick after a rally
+func NewShootingStar() *CandlestickPattern {
+	return newCandlestickPattern("SAO BĂNG",
+		"Shooting star: small body near the low with a long upper wick after a rally",
+		1, true, detectShootingStar)
+}
+
+func detectShootingStar(t CandleThresholds, p []types.Candle) (string, map[string]interface{}) {
+	c := p[0]
+	if t.isSmall(c) && upperWick(c) >= t.LongWick*body(c) && lowerWick(c) <= t.ShortWick*candleRange(c) {
+		return types.Bearish, nil
+	}
+	return "", nil
+}
+
+// NewDoji matches a candle with almost no body. Dragonfly dojis are bullish,
+// gravestone dojis bearish and the rest neutral; the "variant" metadata says which.
+func NewDoji() *CandlestickPattern {
+	return newCandlestickPattern("DOJI",
+		"Doji: open and close almost equal (dragonfly, gravestone, four-price or standard)",
+		1, false, detectDoji)
+}
+
+func detectDoji(t CandleThresholds, p []types.Candle) (string, map[string]interface{}) {
+	c := p[0]
+	if !t.isDoji(c) {
+		return "", nil
+	}
+
+	r := candleRange(c)
+	switch {
+	case r == 0:
+		return types.Neutral, map[string]interface{}{"variant": "four_price"}
+	case upperWick(c) <= t.ShortWick*r:
+		return types.Bullish, map[string]interface{}{"variant": "dragonfly"}
+	case lowerWick(c) <= t.ShortWick*r:
+		return types.Bearish, map[string]interface{}{"variant": "gravestone"}
+	}
+	return types.Neutral, map[string]interface{}{"variant": "standard"}
+}
+
+// NewMorningStar matches a long red candle, a small body below its close and a
+// green candle closing above the first body's midpoint
+func NewMorningStar() *CandlestickPattern {
+	return newCandlestickPattern("SAO MAI",
+		"Morning star: long red candle, small body below it, then a green candle closing above the first body's midpoint",
+		3, true, detectMorningStar)
+}
+
+func detectMorningStar(t CandleThresholds, p []types.Candle) (string, map[string]interface{}) {
+	first, star, last := p[0], p[1], p[2]
+	if isRed(first) && t.isLong(first) &&
+		t.isSmall(star) && bodyMid(star) <= first.Close &&
+		isGreen(last) && !t.isSmall(last) && last.Close > bodyMid(first) {
+		return types.Bullish, nil
+	}
+	return "", nil
+}
+
+// NewEveningStar is the bearish mirror of the morning star
+func NewEveningStar() *CandlestickPattern {
+	return newCandlestickPattern("SAO HÔM",
+		"Evening star: long green candle, small body above it, then a red candle closing below the first body's midpoint",
+		3, true, detectEveningStar)
+}
+
+func detectEveningStar(t CandleThresholds, p []types.Candle) (string, map[string]interface{}) {
+	first, star, last := p[0], p[1], p[2]
+	if isGreen(first) && t.isLong(first) &&
+		t.isSmall(star) && bodyMid(star) >= first.Close &&
+		isRed(last) && !t.isSmall(last) && last.Close < bodyMid(first) {
+		return types.Bearish, nil
+	}
+	return "", nil
+}
+
+// NewHarami matches a small body contained in the previous long body
+func NewHarami() *CandlestickPattern {
+	return newCandlestickPattern("HARAMI",
+		"Harami: a small body inside the previous long body, against the prior move",
+		2, true, detectHarami)
+}
+
+func detectHarami(t CandleThresholds, p []types.Candle) (string, map[string]interface{}) {
+	mother, c := p[0], p[1]
+	if !t.isLong(mother) || body(c) > t.SmallBody*candleRange(mother) ||
+		bodyTop(c) > bodyTop(mother) || bodyBottom(c) < bodyBottom(mother) {
+		return "", nil
+	}
+	if isRed(mother) {
+		return types.Bullish, nil
+	}
+	return types.Bearish, nil
+}
+
+// NewPiercingLine matches a green candle opening at or below a long red close
+// and closing above its midpoint
+func NewPiercingLine() *CandlestickPattern {
+	return newCandlestickPattern("XUYÊN THẤU",
+		"Piercing line: green candle opening at or below a long red close and closing above its midpoint",
+		2, true, detectPiercingLine)
+}
+
+func detectPiercingLine(t CandleThresholds, p []types.Candle) (string, map[string]interface{}) {
+	prev, c := p[0], p[1]
+	if isRed(prev) && t.isLong(prev) && isGreen(c) &&
+		c.Open <= prev.Close && c.Close > bodyMid(prev) && c.Close < prev.Open {
+		return types.Bullish, nil
+	}
+	return "", nil
+}
+
+// NewDarkCloudCover is the bearish mirror of the piercing line
+func NewDarkCloudCover() *CandlestickPattern {
+	return newCandlestickPattern("MÂY ĐEN",
+		"Dark cloud cover: red candle opening at or above a long green close and closing below its midpoint",
+		2, true, detectDarkCloudCover)
+}
+
+func detectDarkCloudCover(t CandleThresholds, p []types.Candle) (string, map[string]interface{}) {
+	prev, c := p[0], p[1]
+	if isGreen(prev) && t.isLong(prev) && isRed(c) &&
+		c.Open >= prev.Close && c.Close < bodyMid(prev) && c.Close > prev.Open {
+		return types.Bearish, nil
+	}
+	return "", nil
+}
+
+// NewThreeWhiteSoldiers matches three long green candles, each opening inside
+// the previous body and closing higher
+func NewThreeWhiteSoldiers() *CandlestickPattern {
+	return newCandlestickPattern("BA CHÀNG LÍNH",
+		"Three white soldiers: three long green candles, each opening inside the previous body and closing higher",
+		3, false, detectThreeSoldiers(true))
+}
+
+// NewThreeBlackCrows is the bearish mirror of three white soldiers
+func NewThreeBlackCrows() *CandlestickPattern {
+	return newCandlestickPattern("BA CON QUẠ",
+		"Three black crows: three long red candles, each opening inside the previous body and closing lower",
+		3, false, detectThreeSoldiers(false))
+}
+
+func detectThreeSoldiers(bullish bool) detectFunc {
+	return func(t CandleThresholds, p []types.Candle) (string, map[string]interface{}) {
+		for i, c := range p {
+			if !t.isLong(c) || (bullish && !isGreen(c)) || (!bullish && !isRed(c)) {
+				return "", nil
+			}
+			if i == 0 {
+				continue
+			}
+			prev := p[i-1]
+			if c.Open < bodyBottom(prev) || c.Open > bodyTop(prev) {
+				return "", nil
+			}
+			if (bullish && c.Close <= prev.Close) || (!bullish && c.Close >= prev.Close) {
+				return "", nil
+			}
+		}
+		if bullish {
+			return types.Bullish, nil
+		}
+		return types.Bearish, nil
+	}
+}
+
+// NewInsideBar matches a candle whose range sits inside the previous (mother)
+// candle. The direction follows the mother candle's colour and the mother's
+// range is reported as the breakout levels.
+func NewInsideBar() *CandlestickPattern {
+	return newCandlestickPattern("INSIDE BAR",
+		"Inside bar: high and low inside the previous candle's range",
+		2, false, detectInsideBar)
+}
+
+func detectInsideBar(t CandleThresholds, p []types.Candle) (string, map[string]interface{}) {
+	mother, c := p[0], p[1]
+	if c.High > mother.High || c.Low < mother.Low || candleRange(c) >= candleRange(mother) || isGreen(mother) == isRed(mother) {
+		return "", nil
+	}
+
+	extra := map[string]interface{}{"mother_high": mother.High, "mother_low": mother.Low}
+	if isGreen(mother) {
+		return types.Bullish, extra
+	}
+	return types.Bearish, extra
+}
+
+// NewPinBar matches a small body with a long nose that pokes past the previous
+// candle's extreme; a lower nose is bullish and an upper nose bearish
+func NewPinBar() *CandlestickPattern {
+	return newCandlestickPattern("PIN BAR",
+		"Pin bar: small body with a long wick rejecting a new high or low beyond the previous candle",
+		2, false, detectPinBar)
+}
+
+func detectPinBar(t CandleThresholds, p []types.Candle) (string, map[string]interface{}) {
+	prev, c := p[0], p[1]
+	if !t.isSmall(c) {
+		return "", nil
+	}
+
+	r := candleRange(c)
+	switch {
+	case lowerWick(c) >= t.PinNose*r && c.Low < prev.Low:
+		return types.Bullish, map[string]interface{}{"nose_ratio": lowerWick(c) / r}
+	case upperWick(c) >= t.PinNose*r && c.High > prev.High:
+		return types.Bearish, map[string]interface{}{"nose_ratio": upperWick(c) / r}
+	}
+	return "", nil
+}
@@ -0,0 +1,150 @@
+package strategies
+
+import (
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+func ohlc(open, high, low, close float64) types.Candle {
+	return types.Candle{Open: open, High: high, Low: low, Close: close, Volume: 1000}
+}
+
+// decline and rally are the three-candle moves that reversal patterns need before them
+func decline() []types.Candle {
+	return []types.Candle{ohlc(130, 131, 119, 120), ohlc(120, 121, 109, 110), ohlc(110, 111, 99, 100)}
+}
+
+func rally() []types.Candle {
+	return []types.Candle{ohlc(70, 81, 69, 80), ohlc(80, 91, 79, 90), ohlc(90, 101, 89, 100)}
+}
+
+func after(move []types.Candle, pattern ...types.Candle) []types.Candle {
+	return append(append([]types.Candle{}, move...), pattern...)
+}
+
+func TestCandlestickPatterns(t *testing.T) {
+	tests := []struct {
+		name          string
+		pattern       *CandlestickPattern
+		candles       []types.Candle
+		wantDirection string // "" means no match
+	}{
+		{"bullish engulfing", NewEngulfing(), after(decline(), ohlc(100, 101, 94, 95), ohlc(94, 103, 93, 102)), types.Bullish},
+		{"bearish engulfing", NewEngulfing(), after(rally(), ohlc(100, 106, 99, 105), ohlc(106, 107, 97, 98)), types.Bearish},
+		{"engulfing against the move", NewEngulfing(), after(rally(), ohlc(100, 101, 94, 95), ohlc(94, 103, 93, 102)), ""},
+		{"engulfing with smaller body", NewEngulfing(), after(decline(), ohlc(100, 101, 89, 90), ohlc(94, 98, 93, 97)), ""},
+
+		{"hammer", NewHammer(), after(decline(), ohlc(98, 100.5, 90, 100)), types.Bullish},
+		{"hammer after rally", NewHammer(), after(rally(), ohlc(98, 100.5, 90, 100)), ""},
+		{"hammer with long upper wick", NewHammer(), after(decline(), ohlc(98, 104, 90, 100)), ""},
+		{"shooting star", NewShootingStar(), after(rally(), ohlc(102, 110, 99.5, 100)), types.Bearish},
+		{"shooting star after decline", NewShootingStar(), after(decline(), ohlc(102, 110, 99.5, 100)), ""},
+
+		{"dragonfly doji", NewDoji(), []types.Candle{ohlc(100, 100.2, 90, 100)}, types.Bullish},
+		{"gravestone doji", NewDoji(), []types.Candle{ohlc(100, 110, 99.9, 100)}, types.Bearish},
+		{"standard doji", NewDoji(), []types.Candle{ohlc(100, 105, 95, 100.5)}, types.Neutral},
+		{"four price doji", NewDoji(), []types.Candle{ohlc(100, 100, 100, 100)}, types.Neutral},
+		{"not a doji", NewDoji(), []types.Candle{ohlc(100, 105, 95, 103)}, ""},
+
+		{"morning star", NewMorningStar(), after(decline(), ohlc(100, 101, 89, 90), ohlc(89, 91, 87, 88), ohlc(88, 98, 87, 97)), types.Bullish},
+		{"morning star weak close", NewMorningStar(), after(decline(), ohlc(100, 101, 89, 90), ohlc(89, 91, 87, 88), ohlc(88, 94, 87, 93)), ""},
+		{"evening star", NewEveningStar(), after(rally(), ohlc(100, 111, 99, 110), ohlc(111, 114, 110, 112), ohlc(112, 113, 102, 103)), types.Bearish},
+
+		{"bullish harami", NewHarami(), after(decline(), ohlc(100, 101, 89, 90), ohlc(92, 95, 91, 94)), types.Bullish},
+		{"bearish harami", NewHarami(), after(rally(), ohlc(100, 111, 99, 110), ohlc(108, 109, 105, 106)), types.Bearish},
+		{"harami body outside", NewHarami(), after(decline(), ohlc(100, 101, 89, 90), ohlc(88, 95, 87, 94)), ""},
+
+		{"piercing line", NewPiercingLine(), after(decline(), ohlc(100, 101, 89, 90), ohlc(89, 98, 88, 97)), types.Bullish},
+		{"piercing below midpoint", NewPiercingLine(), after(decline(), ohlc(100, 101, 89, 90), ohlc(89, 94, 88, 93)), ""},
+		{"dark cloud cover", NewDarkCloudCover(), after(rally(), ohlc(100, 111, 99, 110), ohlc(111, 112, 102, 103)), types.Bearish},
+
+		{"three white soldiers", NewThreeWhiteSoldiers(), []types.Candle{ohlc(100, 111, 99, 110), ohlc(105, 116, 104, 115), ohlc(112, 123, 111, 122)}, types.Bullish},
+		{"soldiers opening above body", NewThreeWhiteSoldiers(), []types.Candle{ohlc(100, 111, 99, 110), ohlc(111, 121, 110, 120), ohlc(115, 126, 114, 125)}, ""},
+		{"three black crows", NewThreeBlackCrows(), []types.Candle{ohlc(122, 123, 111, 112), ohlc(115, 116, 104, 105), ohlc(108, 109, 97, 98)}, types.Bearish},
+
+		{"inside bar after green", NewInsideBar(), []types.Candle{ohlc(100, 112, 98, 110), ohlc(108, 109, 103, 104)}, types.Bullish},
+		{"inside bar after red", NewInsideBar(), []types.Candle{ohlc(110, 112, 98, 100), ohlc(104, 109, 103, 108)}, types.Bearish},
+		{"outside bar", NewInsideBar(), []types.Candle{ohlc(100, 112, 98, 110), ohlc(108, 113, 103, 104)}, ""},
+
+		{"bullish pin bar", NewPinBar(), []types.Candle{ohlc(100, 102, 95, 96), ohlc(97, 98.5, 85, 98)}, types.Bullish},
+		{"bearish pin bar", NewPinBar(), []types.Candle{ohlc(96, 102, 95, 100), ohlc(99, 115, 98.5, 98)}, types.Bearish},
+		{"pin bar inside previous range", NewPinBar(), []types.Candle{ohlc(100, 102, 80, 96), ohlc(97, 98.5, 85, 98)}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := tt.pattern.Match(tt.candles)
+			if err != nil {
+				t.Fatalf("Match() error = %v", err)
+			}
+			if (match != nil) != (tt.wantDirection != "") {
+				t.Fatalf("Match() = %+v, want direction %q", match, tt.wantDirection)
+			}
+			if match == nil {
+				return
+			}
+
+			if match.Direction != tt.wantDirection {
+				t.Errorf("direction = %q, want %q", match.Direction, tt.wantDirection)
+			}
+			if len(match.Candles) != tt.pattern.size {
+				t.Errorf("pattern has %d candles, want %d", len(match.Candles), tt.pattern.size)
+			}
+			if match.Confidence <= 0 || match.Confidence > 1 {
+				t.Errorf("Confidence = %v, want (0, 1]", match.Confidence)
+			}
+		})
+	}
+}
+
+func TestCandlestickPattern_Metadata(t *testing.T) {
+	doji, _ := NewDoji().Match([]types.Candle{ohlc(100, 100.2, 90, 100)})
+	if doji.Metadata["variant"] != "dragonfly" {
+		t.Errorf("doji variant = %v, want dragonfly", doji.Metadata["variant"])
+	}
+
+	inside, _ := NewInsideBar().Match([]types.Candle{ohlc(100, 112, 98, 110), ohlc(108, 109, 103, 104)})
+	if inside.Metadata["mother_high"] != 112.0 || inside.Metadata["mother_low"] != 98.0 {
+		t.Errorf("inside bar levels = %v/%v, want 112/98", inside.Metadata["mother_high"], inside.Metadata["mother_low"])
+	}
+}
+
+func TestCandlestickPattern_Confidence(t *testing.T) {
+	// A clean decline into the hammer: every candle moves its full range down
+	clean := after([]types.Candle{ohlc(130, 130, 120, 120), ohlc(120, 120, 110, 110), ohlc(110, 110, 100, 100)}, ohlc(98, 100.5, 90, 100))
+	// The same net move with wide wicks
+	choppy := after([]types.Candle{ohlc(130, 140, 110, 120), ohlc(120, 130, 100, 110), ohlc(110, 120, 90, 100)}, ohlc(98, 100.5, 90, 100))
+
+	cleanMatch, _ := NewHammer().Match(clean)
+	choppyMatch, _ := NewHammer().Match(choppy)
+	if cleanMatch == nil || choppyMatch == nil {
+		t.Fatal("expected both hammers to match")
+	}
+	if cleanMatch.Confidence != 1 {
+		t.Errorf("clean trend confidence = %v, want 1", cleanMatch.Confidence)
+	}
+	if choppyMatch.Confidence >= cleanMatch.Confidence {
+		t.Errorf("choppy trend confidence = %v, want below %v", choppyMatch.Confidence, cleanMatch.Confidence)
+	}
+}
+
+func TestCandlestickPattern_RequiredCandles(t *testing.T) {
+	hammer := NewHammer()
+	if got := hammer.GetRequiredCandles(); got != 4 {
+		t.Errorf("hammer requires %d candles, want 4", got)
+	}
+	if _, err := hammer.Match([]types.Candle{ohlc(98, 100.5, 90, 100)}); err == nil {
+		t.Error("expected an error without the trend candles")
+	}
+
+	// Without the trend filter a lone hammer matches
+	hammer.Thresholds.TrendBars = 0
+	if match, err := hammer.Match([]types.Candle{ohlc(98, 100.5, 90, 100)}); err != nil || match == nil {
+		t.Errorf("Match() = %v, %v; want match with TrendBars 0", match, err)
+	}
+
+	if got := NewInsideBar().GetRequiredCandles(); got != 2 {
+		t.Errorf("inside bar requires %d candles, want 2", got)
+	}
+}
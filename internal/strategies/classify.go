@@ -0,0 +1,48 @@
+package strategies
+
+import (
+	"github.com/letieu/trade-bot/internal/indicators"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// CandleClassifier colours candles green, red or neutral. A candle without a
+// body is always neutral, and so is one whose body is below either enabled
+// minimum, so dojis neither extend nor break into colour runs.
+type CandleClassifier struct {
+	MinBodyPct float64 // Minimum body as a percentage of the candle's range, 0 disables
+	MinBodyATR float64 // Minimum body in ATRs, 0 disables
+	ATRPeriod  int     // ATR period for MinBodyATR
+}
+
+// Classify returns the colour of every candle. Candles before the ATR is
+// ready are only held to MinBodyPct.
+func (c CandleClassifier) Classify(candles []types.Candle) []types.CandleColor {
+	var atr []float64
+	if c.MinBodyATR > 0 {
+		atr = indicators.ATRSeries(candles, c.ATRPeriod)
+	}
+
+	colors := make([]types.CandleColor, len(candles))
+	for i, candle := range candles {
+		b := body(candle)
+		switch {
+		case b == 0,
+			c.MinBodyPct > 0 && b < c.MinBodyPct/100*candleRange(candle),
+			atr != nil && atr[i] > 0 && b < c.MinBodyATR*atr[i]:
+			colors[i] = types.ColorNeutral
+		case isGreen(candle):
+			colors[i] = types.ColorGreen
+		default:
+			colors[i] = types.ColorRed
+		}
+	}
+	return colors
+}
+
+// Warmup is the extra history the ATR minimum needs
+func (c CandleClassifier) Warmup() int {
+	if c.MinBodyATR > 0 {
+		return c.ATRPeriod
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+package strategies
+
+import (
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+func TestCandleClassifier(t *testing.T) {
+	// Ten candles with a range of 10 give an ATR(5) of 10 from the fifth on
+	wide := func(open, close float64) types.Candle {
+		mid := (open + close) / 2
+		return ohlc(open, mid+5, mid-5, close)
+	}
+	history := []types.Candle{wide(100, 101), wide(101, 102), wide(102, 103), wide(103, 104), wide(104, 105)}
+
+	tests := []struct {
+		name       string
+		classifier CandleClassifier
+		candles    []types.Candle
+		want       types.CandleColor // colour of the last candle
+	}{
+		{"perfect doji", CandleClassifier{}, []types.Candle{ohlc(100, 105, 95, 100)}, types.ColorNeutral},
+		{"four price candle", CandleClassifier{}, []types.Candle{ohlc(100, 100, 100, 100)}, types.ColorNeutral},
+		{"tiny body without a minimum", CandleClassifier{}, []types.Candle{ohlc(100, 105, 95, 100.1)}, types.ColorGreen},
+		{"body below the range minimum", CandleClassifier{MinBodyPct: 10}, []types.Candle{ohlc(100, 105, 95, 99.5)}, types.ColorNeutral},
+		{"body at the range minimum", CandleClassifier{MinBodyPct: 10}, []types.Candle{ohlc(100, 105, 95, 99)}, types.ColorRed},
+		{"body below the ATR minimum", CandleClassifier{MinBodyATR: 0.2, ATRPeriod: 5}, append(history, wide(105, 106)), types.ColorNeutral},
+		{"body above the ATR minimum", CandleClassifier{MinBodyATR: 0.2, ATRPeriod: 5}, append(history, wide(105, 108)), types.ColorGreen},
+		{"ATR not ready yet", CandleClassifier{MinBodyATR: 0.2, ATRPeriod: 5}, history[:2], types.ColorGreen},
+		{"either minimum applies", CandleClassifier{MinBodyPct: 50, MinBodyATR: 0.2, ATRPeriod: 5}, append(history, wide(105, 108)), types.ColorNeutral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			colors := tt.classifier.Classify(tt.candles)
+			if len(colors) != len(tt.candles) {
+				t.Fatalf("got %d colours for %d candles", len(colors), len(tt.candles))
+			}
+			if got := colors[len(colors)-1]; got != tt.want {
+				t.Errorf("colour = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifier_Strategies(t *testing.T) {
+	doji := createCandle(100, 100)
+	smallGreen := ohlc(70, 80, 60, 70.5) // body 2.5% of its range
+
+	tests := []struct {
+		name      string
+		strategy  types.PatternMatcher
+		candles   []types.Candle
+		wantMatch bool
+	}{
+		{"run ending in a doji", NewConsecutiveCandles(3), []types.Candle{createCandle(90, 95), createCandle(95, 100), createCandle(100, 105), doji}, false},
+		{"doji breaks the run", NewConsecutiveCandles(4), []types.Candle{createCandle(90, 95), doji, createCandle(95, 100), createCandle(100, 105), createCandle(105, 110)}, false},
+		{"run after a doji", NewConsecutiveCandles(3), []types.Candle{doji, createCandle(95, 100), createCandle(100, 105), createCandle(105, 110)}, true},
+		{"reversal into a doji", NewCandleReversal(3), []types.Candle{createCandle(100, 90), createCandle(90, 80), createCandle(80, 70), createCandle(70, 70)}, false},
+		{"doji inside the run", NewCandleReversal(3), []types.Candle{createCandle(100, 90), createCandle(90, 90), createCandle(90, 80), createCandle(80, 85)}, false},
+		{"run of dojis", NewCandleReversal(3), []types.Candle{doji, doji, doji, createCandle(100, 105)}, false},
+		{"small reversal body", NewCandleReversal(3), []types.Candle{createCandle(100, 90), createCandle(90, 80), createCandle(80, 70), smallGreen}, true},
+		{"small reversal body below the minimum", &ThreeCandleReversal{Count: 3, Classifier: CandleClassifier{MinBodyPct: 10}},
+			[]types.Candle{createCandle(100, 90), createCandle(90, 80), createCandle(80, 70), smallGreen}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := tt.strategy.Match(tt.candles)
+			if err != nil {
+				t.Fatalf("Match() error = %v", err)
+			}
+			if (match != nil) != tt.wantMatch {
+				t.Errorf("Match() = %+v, want match %v", match, tt.wantMatch)
+			}
+		})
+	}
+}
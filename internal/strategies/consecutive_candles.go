@@ -2,53 +2,68 @@ package strategies
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/letieu/trade-bot/internal/types"
 )
 
 type ConsecutiveCandles struct {
-	MinCount int
+	MinCount   int
+	Classifier CandleClassifier // Neutral candles end a run
 }
 
 func NewConsecutiveCandles(minCount int) *ConsecutiveCandles {
 	return &ConsecutiveCandles{MinCount: minCount}
 }
 
-func (s *ConsecutiveCandles) countConsecutive(candles []types.Candle) int {
-	if len(candles) == 0 {
-		return 0
+// countConsecutive returns the length and colour of the run ending at the
+// last candle; a neutral last candle has no run
+func (s *ConsecutiveCandles) countConsecutive(candles []types.Candle) (int, types.CandleColor) {
+	colors := s.Classifier.Classify(candles)
+	if len(colors) == 0 || colors[len(colors)-1] == types.ColorNeutral {
+		return 0, types.ColorNeutral
 	}
 
 	// Check from the end backward to find consecutive candles of same color
-	lastCandle := candles[len(candles)-1]
-	targetColor := lastCandle.Color()
+	targetColor := colors[len(colors)-1]
 	consecutiveCount := 1
 
-	for i := len(candles) - 2; i >= 0; i-- {
-		if candles[i].Color() == targetColor {
+	for i := len(colors) - 2; i >= 0; i-- {
+		if colors[i] == targetColor {
 			consecutiveCount++
 		} else {
 			break
 		}
 	}
 
-	return consecutiveCount
+	return consecutiveCount, targetColor
 }
 
-func (s *ConsecutiveCandles) Match(candles []types.Candle) (bool, error) {
+// Match reports the colour of the run as its direction. Confidence grows with
+// the run length and reaches 1 at twice MinCount.
+func (s *ConsecutiveCandles) Match(candles []types.Candle) (*types.MatchResult, error) {
 	if len(candles) < s.MinCount {
-		return false, fmt.Errorf("need at least %d candles, got %d", s.MinCount, len(candles))
+		return nil, fmt.Errorf("need at least %d candles, got %d", s.MinCount, len(candles))
 	}
 
-	consecutiveCount := s.countConsecutive(candles)
-	return consecutiveCount >= s.MinCount, nil
-}
+	consecutiveCount, color := s.countConsecutive(candles)
+	if consecutiveCount < s.MinCount {
+		return nil, nil
+	}
 
-func (s *ConsecutiveCandles) GetMetadata(candles []types.Candle) map[string]interface{} {
-	count := s.countConsecutive(candles)
-	return map[string]interface{}{
-		"consecutive_count": count,
+	direction := types.Bullish
+	if color == types.ColorRed {
+		direction = types.Bearish
 	}
+
+	return &types.MatchResult{
+		Direction:  direction,
+		Confidence: math.Min(1, float64(consecutiveCount)/float64(2*s.MinCount)),
+		Candles:    candles[len(candles)-consecutiveCount:],
+		Metadata: map[string]interface{}{
+			"consecutive_count": consecutiveCount,
+		},
+	}, nil
 }
 
 func (s *ConsecutiveCandles) GetName() string {
@@ -60,5 +75,5 @@ func (s *ConsecutiveCandles) GetDescription() string {
 }
 
 func (s *ConsecutiveCandles) GetRequiredCandles() int {
-	return s.MinCount + 1
+	return s.MinCount + 1 + s.Classifier.Warmup()
 }
@@ -0,0 +1,66 @@
+package strategies
+
+import (
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+func TestConsecutiveCandles_Match(t *testing.T) {
+	strategy := NewConsecutiveCandles(3)
+
+	tests := []struct {
+		name           string
+		candles        []types.Candle
+		wantDirection  string
+		wantCount      int
+		wantConfidence float64
+	}{
+		{
+			name:           "green run",
+			candles:        []types.Candle{createCandle(100, 90), createCandle(90, 95), createCandle(95, 100), createCandle(100, 105)},
+			wantDirection:  types.Bullish,
+			wantCount:      3,
+			wantConfidence: 0.5,
+		},
+		{
+			name:           "long red run",
+			candles:        []types.Candle{createCandle(100, 95), createCandle(95, 90), createCandle(90, 85), createCandle(85, 80), createCandle(80, 75), createCandle(75, 70)},
+			wantDirection:  types.Bearish,
+			wantCount:      6,
+			wantConfidence: 1,
+		},
+		{
+			name:    "run too short",
+			candles: []types.Candle{createCandle(100, 105), createCandle(105, 100), createCandle(100, 95)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := strategy.Match(tt.candles)
+			if err != nil {
+				t.Fatalf("Match() error = %v", err)
+			}
+			if tt.wantDirection == "" {
+				if match != nil {
+					t.Errorf("Match() = %+v, want no match", match)
+				}
+				return
+			}
+			if match == nil {
+				t.Fatal("Match() = nil, want a match")
+			}
+
+			if match.Direction != tt.wantDirection {
+				t.Errorf("Direction = %q, want %q", match.Direction, tt.wantDirection)
+			}
+			if match.Metadata["consecutive_count"] != tt.wantCount || len(match.Candles) != tt.wantCount {
+				t.Errorf("count = %v with %d candles, want %d", match.Metadata["consecutive_count"], len(match.Candles), tt.wantCount)
+			}
+			if match.Confidence != tt.wantConfidence {
+				t.Errorf("Confidence = %v, want %v", match.Confidence, tt.wantConfidence)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package strategies
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/letieu/trade-bot/internal/indicators"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// Oscillators a Divergence can compare price with
+const (
+	OscillatorRSI  = "rsi"
+	OscillatorMACD = "macd" // MACD histogram
+)
+
+// Kinds of divergence
+const (
+	DivergenceRegular = "regular"
+	DivergenceHidden  = "hidden"
+	DivergenceBoth    = "both"
+)
+
+// Divergence compares the last two swing lows (or highs) in price with the
+// oscillator at the same swings. Regular divergences (price lower low,
+// oscillator higher low) point to a reversal, hidden ones (price higher low,
+// oscillator lower low) to a continuation; highs mirror this for bearish.
+type Divergence struct {
+	Oscillator string // OscillatorRSI or OscillatorMACD
+	Period     int    // RSI period
+	Fast       int    // MACD fast, slow and signal periods
+	Slow       int
+	Signal     int
+	Window     int    // Candles searched for the two swings
+	PivotBars  int    // Candles on each side a swing must exceed
+	Kind       string // DivergenceRegular, DivergenceHidden or DivergenceBoth
+}
+
+func NewRSIDivergence(period, window, pivotBars int, kind string) *Divergence {
+	return &Divergence{Oscillator: OscillatorRSI, Period: period, Window: window, PivotBars: pivotBars, Kind: kind}
+}
+
+func NewMACDDivergence(fast, slow, signal, window, pivotBars int, kind string) *Divergence {
+	return &Divergence{Oscillator: OscillatorMACD, Fast: fast, Slow: slow, Signal: signal, Window: window, PivotBars: pivotBars, Kind: kind}
+}
+
+// Match fires on the candle that confirms the second swing, PivotBars after
+// it. Confidence is the oscillator's move between the swings: 10 RSI points,
+// or a histogram change as large as the bigger of the two readings, scores 1.
+func (s *Divergence) Match(candles []types.Candle) (*types.MatchResult, error) {
+	if len(candles) < s.GetRequiredCandles() {
+		return nil, fmt.Errorf("need at least %d candles, got %d", s.GetRequiredCandles(), len(candles))
+	}
+
+	osc := s.series(candles)
+	start := len(candles) - s.Window
+
+	for _, lows := range []bool{true, false} {
+		d := findDivergence(candles, osc, start, s.PivotBars, lows)
+		if d == nil || !s.wants(d.kind) {
+			continue
+		}
+
+		change := math.Abs(d.second.value - d.first.value)
+		confidence := change / 10
+		if s.Oscillator == OscillatorMACD {
+			confidence = change / math.Max(math.Abs(d.first.value), math.Abs(d.second.value))
+		}
+
+		return &types.MatchResult{
+			Direction:  d.direction,
+			Confidence: math.Min(1, confidence),
+			Candles:    candles[d.first.index:],
+			Metadata: map[string]interface{}{
+				"divergence":   d.kind,
+				"oscillator":   s.Oscillator,
+				"first_pivot":  candles[d.first.index].Timestamp,
+				"second_pivot": candles[d.second.index].Timestamp,
+				"first_value":  d.first.value,
+				"second_value": d.second.value,
+			},
+		}, nil
+	}
+
+	return nil, nil
+}
+
+func (s *Divergence) wants(kind string) bool {
+	return s.Kind == DivergenceBoth || s.Kind == kind
+}
+
+func (s *Divergence) series(candles []types.Candle) []float64 {
+	if s.Oscillator == OscillatorMACD {
+		macd := indicators.MACDSeries(candles, s.Fast, s.Slow, s.Signal)
+		histogram := make([]float64, len(macd))
+		for i, v := range macd {
+			histogram[i] = v.Histogram
+		}
+		return histogram
+	}
+	return indicators.RSISeries(candles, s.Period)
+}
+
+// swing is a price pivot and the oscillator reading paired with it
+type swing struct {
+	index int
+	value float64
+}
+
+type divergence struct {
+	kind          string
+	direction     string
+	first, second swing
+}
+
+// findDivergence compares the swing lows (or highs) of candles[start:] whose
+// latest one was confirmed by the last candle. The oscillator reading of a
+// swing is its own extreme within pivotBars of the price pivot, as the two
+// rarely turn on the same candle.
+func findDivergence(candles []types.Candle, osc []float64, start, pivotBars int, lows bool) *divergence {
+	price := func(i int) float64 {
+		if lows {
+			return candles[i].Low
+		}
+		return candles[i].High
+	}
+	// beyond reports whether a is a more extreme swing than b
+	beyond := func(a, b float64) bool {
+		if lows {
+			return a < b
+		}
+		return a > b
+	}
+
+	isPivot := func(i int) bool {
+		for j := i - pivotBars; j <= i+pivotBars; j++ {
+			if j != i && !beyond(price(i), price(j)) {
+				return false
+			}
+		}
+		return true
+	}
+
+	second := len(candles) - 1 - pivotBars
+	if second-pivotBars < start || !isPivot(second) {
+		return nil
+	}
+	first := -1
+	for i := second - 1; i-pivotBars >= start; i-- {
+		if isPivot(i) {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return nil
+	}
+
+	reading := func(i int) swing {
+		value := osc[i]
+		for j := i - pivotBars; j <= i+pivotBars; j++ {
+			if beyond(osc[j], value) {
+				value = osc[j]
+			}
+		}
+		return swing{index: i, value: value}
+	}
+	d := &divergence{first: reading(first), second: reading(second)}
+
+	priceBeyond := beyond(price(second), price(first))
+	oscBeyond := beyond(d.second.value, d.first.value)
+	switch {
+	case priceBeyond && !oscBeyond && d.second.value != d.first.value:
+		d.kind = DivergenceRegular
+	case !priceBeyond && oscBeyond && price(second) != price(first):
+		d.kind = DivergenceHidden
+	default:
+		return nil
+	}
+
+	// Both kinds at lows are bullish, at highs bearish
+	d.direction = types.Bullish
+	if !lows {
+		d.direction = types.Bearish
+	}
+	return d
+}
+
+func (s *Divergence) GetName() string {
+	return "PHÂN KỲ"
+}
+
+func (s *Divergence) GetDescription() string {
+	return fmt.Sprintf("Detects %s divergences between price swings and %s over %d candles", s.Kind, s.Oscillator, s.Window)
+}
+
+// GetRequiredCandles covers the search window plus five periods for the
+// oscillator to settle
+func (s *Divergence) GetRequiredCandles() int {
+	warmup := 5 * s.Period
+	if s.Oscillator == OscillatorMACD {
+		warmup = 5*s.Slow + s.Signal
+	}
+	return s.Window + warmup
+}
@@ -0,0 +1,110 @@
+package strategies
+
+import (
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// swings builds candles whose lows and highs sit one point either side of
+// each price, so both trace the same swings
+func swings(prices ...float64) []types.Candle {
+	candles := make([]types.Candle, len(prices))
+	for i, p := range prices {
+		candles[i] = types.Candle{Timestamp: int64(i), Open: p, High: p + 1, Low: p - 1, Close: p}
+	}
+	return candles
+}
+
+// oscillator is flat at 50 except for the given readings
+func oscillator(n int, readings map[int]float64) []float64 {
+	osc := make([]float64, n)
+	for i := range osc {
+		osc[i] = 50
+	}
+	for i, v := range readings {
+		osc[i] = v
+	}
+	return osc
+}
+
+func TestFindDivergence(t *testing.T) {
+	lowerLow := swings(10, 9, 8, 7, 8, 9, 10, 9, 8, 6.5, 7.5, 8.5)
+	higherLow := swings(10, 9, 8, 7, 8, 9, 10, 9, 8, 7.5, 8, 8.5)
+	higherHigh := swings(0, 1, 2, 3, 2, 1, 0, 1, 2, 3.5, 2.5, 1.5)
+
+	tests := []struct {
+		name          string
+		candles       []types.Candle
+		osc           map[int]float64
+		lows          bool
+		wantKind      string // "" means no divergence
+		wantDirection string
+	}{
+		{"regular bullish", lowerLow, map[int]float64{3: 25, 9: 35}, true, DivergenceRegular, types.Bullish},
+		{"hidden bullish", higherLow, map[int]float64{3: 35, 9: 25}, true, DivergenceHidden, types.Bullish},
+		{"lower low confirmed by the oscillator", lowerLow, map[int]float64{3: 35, 9: 25}, true, "", ""},
+		{"oscillator low near the pivot", lowerLow, map[int]float64{2: 25, 10: 35}, true, DivergenceRegular, types.Bullish},
+		{"regular bearish", higherHigh, map[int]float64{3: 75, 9: 65}, false, DivergenceRegular, types.Bearish},
+		{"second swing not confirmed", lowerLow[:11], map[int]float64{3: 25, 9: 35}, true, "", ""},
+		{"single swing", swings(8, 9, 10, 9, 8, 6.5, 7.5, 8.5), map[int]float64{5: 35}, true, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := findDivergence(tt.candles, oscillator(len(tt.candles), tt.osc), 0, 2, tt.lows)
+			if (d != nil) != (tt.wantKind != "") {
+				t.Fatalf("findDivergence() = %+v, want kind %q", d, tt.wantKind)
+			}
+			if d == nil {
+				return
+			}
+			if d.kind != tt.wantKind || d.direction != tt.wantDirection {
+				t.Errorf("got %s %s, want %s %s", d.kind, d.direction, tt.wantKind, tt.wantDirection)
+			}
+			if tt.candles[d.first.index].Timestamp != 3 || tt.candles[d.second.index].Timestamp != 9 {
+				t.Errorf("pivots at %d and %d, want 3 and 9", tt.candles[d.first.index].Timestamp, tt.candles[d.second.index].Timestamp)
+			}
+		})
+	}
+}
+
+func TestDivergence_RSI(t *testing.T) {
+	// A steep drop, a bounce, then a slower grind to a marginally lower low:
+	// price makes a lower low while RSI makes a higher one
+	var prices []float64
+	price := 100.0
+	for i := 0; i < 40; i++ {
+		price += float64(i%2)*2 - 1
+		prices = append(prices, price)
+	}
+	for _, step := range []float64{-3, -3, -3, -3, -3, 2, 2, 2, 2, 2, -3, 1, -3, 1, -3, 1, -3, 1, -3, -2, 2, 2} {
+		price += step
+		prices = append(prices, price)
+	}
+	candles := swings(prices...)
+
+	divergence := NewRSIDivergence(5, 30, 2, DivergenceBoth)
+	if got := divergence.GetRequiredCandles(); got != 55 {
+		t.Errorf("GetRequiredCandles() = %d, want 55", got)
+	}
+
+	match, err := divergence.Match(candles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match == nil {
+		t.Fatal("expected a regular bullish divergence")
+	}
+	if match.Direction != types.Bullish || match.Metadata["divergence"] != DivergenceRegular {
+		t.Errorf("got %s %v, want bullish regular", match.Direction, match.Metadata["divergence"])
+	}
+	if match.Metadata["first_pivot"] != candles[44].Timestamp || match.Metadata["second_pivot"] != candles[59].Timestamp {
+		t.Errorf("pivots = %v, %v; want %d, %d", match.Metadata["first_pivot"], match.Metadata["second_pivot"], candles[44].Timestamp, candles[59].Timestamp)
+	}
+
+	// Only hidden divergences wanted
+	if match, _ := NewRSIDivergence(5, 30, 2, DivergenceHidden).Match(candles); match != nil {
+		t.Errorf("hidden only: got %+v, want no match", match)
+	}
+}
@@ -0,0 +1,126 @@
+package strategies
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/letieu/trade-bot/internal/indicators"
+	"github.com/letieu/trade-bot/internal/levels"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// Events a NearLevel reports
+const (
+	LevelTouch  = "touch"  // closed inside a zone
+	LevelReject = "reject" // wicked into a zone and closed back out on the side it came from
+	LevelBreak  = "break"  // closed through a zone from the other side
+)
+
+// NearLevel signals when the last closed candle touches, rejects or breaks a
+// support/resistance zone found in the Lookback candles before it
+type NearLevel struct {
+	Lookback  int           // Candles the zones are derived from
+	ATRPeriod int           // ATR the zone width is measured in
+	Width     float64       // Zone width in ATRs
+	Levels    levels.Config // Zone detection; Width is set from the ATR on each match
+}
+
+func NewNearLevel(lookback, atrPeriod int, width float64, cfg levels.Config) *NearLevel {
+	return &NearLevel{Lookback: lookback, ATRPeriod: atrPeriod, Width: width, Levels: cfg}
+}
+
+// Match reports breaks in their direction, rejections as bounces off support
+// (bullish) or resistance (bearish) and touches as neutral. With several
+// zones in play a break wins over a rejection over a touch, then the nearest
+// zone. Confidence is the zone's pivot count against twice MinTouches, 1 for
+// zones holding a high volume node.
+func (s *NearLevel) Match(candles []types.Candle) (*types.MatchResult, error) {
+	if len(candles) < s.GetRequiredCandles() {
+		return nil, fmt.Errorf("need at least %d candles, got %d", s.GetRequiredCandles(), len(candles))
+	}
+
+	history := candles[len(candles)-s.Lookback-1 : len(candles)-1]
+	atrSeries := indicators.ATRSeries(history, s.ATRPeriod)
+	atr := atrSeries[len(atrSeries)-1]
+	if atr <= 0 {
+		return nil, nil
+	}
+
+	cfg := s.Levels
+	cfg.Width = s.Width * atr
+	zones := levels.Find(history, cfg)
+
+	prev, last := history[len(history)-1], candles[len(candles)-1]
+	var best *levels.Zone
+	event, direction, rank := "", "", 0
+	for i := range zones {
+		zone := &zones[i]
+		e, d := levelEvent(*zone, prev, last)
+		r := levelEventRank[e]
+		if r == 0 {
+			continue
+		}
+		if r > rank || (r == rank && math.Abs(zone.Mid()-last.Close) < math.Abs(best.Mid()-last.Close)) {
+			best, event, direction, rank = zone, e, d, r
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+
+	confidence := 1.0
+	if !best.HighVolume && cfg.MinTouches > 0 {
+		confidence = math.Min(1, float64(best.Touches)/float64(2*cfg.MinTouches))
+	}
+
+	// The zone supports price coming from above and resists it from below
+	role := "support"
+	if prev.Close < best.Mid() {
+		role = "resistance"
+	}
+
+	return &types.MatchResult{
+		Direction:  direction,
+		Confidence: confidence,
+		Candles:    candles[len(candles)-1:],
+		Metadata: map[string]interface{}{
+			"event":        event,
+			"zone_low":     best.Low,
+			"zone_high":    best.High,
+			"zone_role":    role,
+			"zone_touches": best.Touches,
+		},
+	}, nil
+}
+
+// levelEventRank orders events when several zones are in play
+var levelEventRank = map[string]int{LevelTouch: 1, LevelReject: 2, LevelBreak: 3}
+
+// levelEvent classifies last against zone, given the candle before it
+func levelEvent(zone levels.Zone, prev, last types.Candle) (event, direction string) {
+	switch {
+	case prev.Close <= zone.High && last.Close > zone.High:
+		return LevelBreak, types.Bullish
+	case prev.Close >= zone.Low && last.Close < zone.Low:
+		return LevelBreak, types.Bearish
+	case prev.Close > zone.High && last.Low <= zone.High && last.Close > zone.High:
+		return LevelReject, types.Bullish
+	case prev.Close < zone.Low && last.High >= zone.Low && last.Close < zone.Low:
+		return LevelReject, types.Bearish
+	case zone.Contains(last.Close):
+		return LevelTouch, types.Neutral
+	}
+	return "", ""
+}
+
+func (s *NearLevel) GetName() string {
+	return "VÙNG GIÁ"
+}
+
+func (s *NearLevel) GetDescription() string {
+	return fmt.Sprintf("Detects touches, rejections and breaks of support/resistance zones from the last %d candles", s.Lookback)
+}
+
+func (s *NearLevel) GetRequiredCandles() int {
+	return s.Lookback + 1
+}
@@ -0,0 +1,76 @@
+package strategies
+
+import (
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/levels"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// zigzag swings between support at 100 and resistance at 110, ending at 105
+func zigzag(n int) []types.Candle {
+	prices := []float64{105, 110, 105, 100}
+	candles := make([]types.Candle, n)
+	for i := range candles {
+		p := prices[i%len(prices)]
+		candles[i] = ohlc(p, p+0.5, p-0.5, p)
+	}
+	candles[n-1] = ohlc(105, 105.5, 104.5, 105)
+	return candles
+}
+
+func TestNearLevel(t *testing.T) {
+	tests := []struct {
+		name          string
+		last          types.Candle
+		wantEvent     string // "" means no match
+		wantDirection string
+		wantRole      string
+	}{
+		{"rejected at support", ohlc(104, 105, 99.5, 103), LevelReject, types.Bullish, "support"},
+		{"touching support", ohlc(103, 103.5, 100, 100.5), LevelTouch, types.Neutral, "support"},
+		{"breaking support", ohlc(103, 103.5, 95.5, 96), LevelBreak, types.Bearish, "support"},
+		{"breaking resistance", ohlc(106, 114.5, 105.5, 114), LevelBreak, types.Bullish, "resistance"},
+		{"between the zones", ohlc(105, 106.5, 104.5, 106), "", "", ""},
+	}
+
+	strategy := NewNearLevel(20, 5, 0.5, levels.Config{PivotBars: 1, MinTouches: 2})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := strategy.Match(append(zigzag(20), tt.last))
+			if err != nil {
+				t.Fatalf("Match() error = %v", err)
+			}
+			if (match != nil) != (tt.wantEvent != "") {
+				t.Fatalf("Match() = %+v, want event %q", match, tt.wantEvent)
+			}
+			if match == nil {
+				return
+			}
+
+			if match.Metadata["event"] != tt.wantEvent || match.Direction != tt.wantDirection {
+				t.Errorf("got %v %s, want %s %s", match.Metadata["event"], match.Direction, tt.wantEvent, tt.wantDirection)
+			}
+			if match.Metadata["zone_role"] != tt.wantRole {
+				t.Errorf("zone_role = %v, want %s", match.Metadata["zone_role"], tt.wantRole)
+			}
+			low, high := match.Metadata["zone_low"].(float64), match.Metadata["zone_high"].(float64)
+			if low >= high {
+				t.Errorf("zone bounds = %v-%v, want low below high", low, high)
+			}
+			if match.Confidence != 1 {
+				t.Errorf("Confidence = %v, want 1 for zones touched four or more times", match.Confidence)
+			}
+		})
+	}
+}
+
+func TestNearLevel_RequiredCandles(t *testing.T) {
+	strategy := NewNearLevel(20, 5, 0.5, levels.Config{PivotBars: 1, MinTouches: 2})
+	if got := strategy.GetRequiredCandles(); got != 21 {
+		t.Errorf("GetRequiredCandles() = %d, want 21", got)
+	}
+	if _, err := strategy.Match(zigzag(20)); err == nil {
+		t.Error("expected an error without the full lookback")
+	}
+}
@@ -0,0 +1,464 @@
+package strategies
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/levels"
+	"github.com/letieu/trade-bot/internal/rules"
+	"github.com/letieu/trade-bot/internal/transform"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// Factory builds a strategy, reading its parameters from params
+type Factory func(params *Params) (types.PatternMatcher, error)
+
+// Registry builds strategies by name
+type Registry struct {
+	factories map[string]Factory
+}
+
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// DefaultRegistry knows every strategy in this package
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+
+	r.Register("consecutive", func(p *Params) (types.PatternMatcher, error) {
+		minCount := p.Int("minCount", 3)
+		if minCount < 1 {
+			return nil, fmt.Errorf("minCount must be at least 1, got %d", minCount)
+		}
+		classifier, err := classifierParams(p)
+		if err != nil {
+			return nil, err
+		}
+		strategy := NewConsecutiveCandles(minCount)
+		strategy.Classifier = classifier
+		return strategy, nil
+	})
+	r.Register("reversal", func(p *Params) (types.PatternMatcher, error) {
+		count := p.Int("count", 3)
+		if count < 1 {
+			return nil, fmt.Errorf("count must be at least 1, got %d", count)
+		}
+		classifier, err := classifierParams(p)
+		if err != nil {
+			return nil, err
+		}
+		strategy := NewCandleReversal(count)
+		strategy.Classifier = classifier
+		return strategy, nil
+	})
+	r.Register("volumeSpike", func(p *Params) (types.PatternMatcher, error) {
+		period := p.Int("period", 20)
+		multiple := p.Float("multiple", 3)
+		zScore := p.Float("zScore", 0)
+		minChange := p.Float("minChange", 0)
+		if period < 2 {
+			return nil, fmt.Errorf("period must be at least 2, got %d", period)
+		}
+		if multiple <= 0 && zScore <= 0 {
+			return nil, fmt.Errorf("multiple or zScore must be positive")
+		}
+		if multiple < 0 || zScore < 0 || minChange < 0 {
+			return nil, fmt.Errorf("multiple, zScore and minChange must not be negative")
+		}
+		return NewVolumeSpike(period, multiple, zScore, minChange), nil
+	})
+	r.Register("breakout", func(p *Params) (types.PatternMatcher, error) {
+		lookback := p.Int("lookback", 20)
+		confirm := p.Int("confirm", 0)
+		atrPeriod := p.Int("atrPeriod", 14)
+		minATR := p.Float("minATR", 0)
+		if lookback < 1 || atrPeriod < 1 {
+			return nil, fmt.Errorf("lookback and atrPeriod must be at least 1, got %d and %d", lookback, atrPeriod)
+		}
+		if confirm < 0 || minATR < 0 {
+			return nil, fmt.Errorf("confirm and minATR must not be negative")
+		}
+		return NewBreakout(lookback, confirm, atrPeriod, minATR), nil
+	})
+	r.Register("nearLevel", func(p *Params) (types.PatternMatcher, error) {
+		lookback := p.Int("lookback", 200)
+		atrPeriod := p.Int("atrPeriod", 14)
+		width := p.Float("width", 0.5)
+		cfg := levels.Config{
+			PivotBars:    p.Int("pivotBars", 3),
+			MinTouches:   p.Int("minTouches", 2),
+			Bins:         p.Int("bins", 50),
+			VolumeFactor: p.Float("volumeFactor", 2),
+		}
+		if atrPeriod < 1 || cfg.PivotBars < 1 || cfg.MinTouches < 1 {
+			return nil, fmt.Errorf("atrPeriod, pivotBars and minTouches must be at least 1")
+		}
+		// ATR is only ready once it has atrPeriod+1 candles of history
+		if lookback < 2*cfg.PivotBars+1 || lookback <= atrPeriod {
+			return nil, fmt.Errorf("lookback must cover atrPeriod+1 and 2*pivotBars+1 candles, got %d", lookback)
+		}
+		if width <= 0 || cfg.Bins < 0 || cfg.VolumeFactor <= 0 {
+			return nil, fmt.Errorf("width and volumeFactor must be positive and bins not negative")
+		}
+		return NewNearLevel(lookback, atrPeriod, width, cfg), nil
+	})
+	r.Register("divergence", func(p *Params) (types.PatternMatcher, error) {
+		oscillator := p.String("oscillator", OscillatorRSI)
+		period := p.Int("period", 14)
+		fast, slow, signal := p.Int("fast", 12), p.Int("slow", 26), p.Int("signal", 9)
+		window := p.Int("window", 50)
+		pivotBars := p.Int("pivotBars", 3)
+		kind := p.String("kind", DivergenceBoth)
+
+		switch kind {
+		case DivergenceRegular, DivergenceHidden, DivergenceBoth:
+		default:
+			return nil, fmt.Errorf("kind must be %q, %q or %q, got %q", DivergenceRegular, DivergenceHidden, DivergenceBoth, kind)
+		}
+		if pivotBars < 1 || window < 4*pivotBars+2 {
+			return nil, fmt.Errorf("pivotBars must be at least 1 and window at least 4*pivotBars+2, got %d and %d", pivotBars, window)
+		}
+
+		switch oscillator {
+		case OscillatorRSI:
+			if period < 1 {
+				return nil, fmt.Errorf("period must be at least 1, got %d", period)
+			}
+			return NewRSIDivergence(period, window, pivotBars, kind), nil
+		case OscillatorMACD:
+			if fast < 1 || slow <= fast || signal < 1 {
+				return nil, fmt.Errorf("MACD periods must satisfy 1 <= fast < slow and signal >= 1, got %d/%d/%d", fast, slow, signal)
+			}
+			return NewMACDDivergence(fast, slow, signal, window, pivotBars, kind), nil
+		default:
+			return nil, fmt.Errorf("oscillator must be %q or %q, got %q", OscillatorRSI, OscillatorMACD, oscillator)
+		}
+	})
+
+	r.Register("rule", func(p *Params) (types.PatternMatcher, error) {
+		expr := p.String("expr", "")
+		label := p.String("label", "")
+		direction := p.String("direction", "")
+		if expr == "" {
+			return nil, fmt.Errorf("missing parameter expr")
+		}
+		return rules.Compile(label, expr, direction)
+	})
+
+	candlesticks := map[string]func() *CandlestickPattern{
+		"engulfing":          NewEngulfing,
+		"hammer":             NewHammer,
+		"shootingStar":       NewShootingStar,
+		"doji":               NewDoji,
+		"morningStar":        NewMorningStar,
+		"eveningStar":        NewEveningStar,
+		"harami":             NewHarami,
+		"piercingLine":       NewPiercingLine,
+		"darkCloudCover":     NewDarkCloudCover,
+		"threeWhiteSoldiers": NewThreeWhiteSoldiers,
+		"threeBlackCrows":    NewThreeBlackCrows,
+		"insideBar":          NewInsideBar,
+		"pinBar":             NewPinBar,
+	}
+	for name, constructor := range candlesticks {
+		r.Register(name, candlestickFactory(constructor))
+	}
+
+	return r
+}
+
+// classifierParams reads the minimum body a candle needs to count as green or red
+func classifierParams(p *Params) (CandleClassifier, error) {
+	c := CandleClassifier{
+		MinBodyPct: p.Float("minBodyPct", 0),
+		MinBodyATR: p.Float("minBodyATR", 0),
+		ATRPeriod:  p.Int("atrPeriod", 14),
+	}
+	if c.MinBodyPct < 0 || c.MinBodyPct > 100 || c.MinBodyATR < 0 || c.ATRPeriod < 1 {
+		return c, fmt.Errorf("minBodyPct must be 0-100, minBodyATR not negative and atrPeriod at least 1")
+	}
+	return c, nil
+}
+
+// candlestickFactory exposes the shared thresholds as parameters
+func candlestickFactory(constructor func() *CandlestickPattern) Factory {
+	return func(p *Params) (types.PatternMatcher, error) {
+		pattern := constructor()
+		t := &pattern.Thresholds
+		t.DojiBody = p.Float("dojiBody", t.DojiBody)
+		t.SmallBody = p.Float("smallBody", t.SmallBody)
+		t.LongBody = p.Float("longBody", t.LongBody)
+		t.LongWick = p.Float("longWick", t.LongWick)
+		t.ShortWick = p.Float("shortWick", t.ShortWick)
+		t.PinNose = p.Float("pinNose", t.PinNose)
+		t.TrendBars = p.Int("trendBars", t.TrendBars)
+		if t.TrendBars < 0 {
+			return nil, fmt.Errorf("trendBars must not be negative, got %d", t.TrendBars)
+		}
+		return pattern, nil
+	}
+}
+
+func (r *Registry) Register(name string, factory Factory) {
+	r.factories[name] = factory
+}
+
+// Names returns the registered strategy names in alphabetical order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build creates the named strategy, rejecting unknown names and parameters
+func (r *Registry) Build(name string, params map[string]interface{}) (types.PatternMatcher, error) {
+	factory, ok := r.factories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %q (available: %s)", name, strings.Join(r.Names(), ", "))
+	}
+
+	p := newParams(params)
+	strategy, err := factory(p)
+	if err == nil {
+		err = p.err
+	}
+	if err == nil {
+		err = p.checkUnused()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("strategy %q: %w", name, err)
+	}
+	return strategy, nil
+}
+
+// Confluence modes
+const (
+	ConfluenceTag     = "tag"     // mark whether the higher timeframe agrees
+	ConfluenceRequire = "require" // drop signals the higher timeframe does not confirm
+)
+
+// Configured is a strategy built from config and the intervals it runs on
+type Configured struct {
+	Name       string
+	Matcher    types.PatternMatcher
+	Intervals  []string // empty means every interval
+	Confluence config.ConfluenceConfig
+}
+
+// RunsOn reports whether the strategy applies to interval
+func (c Configured) RunsOn(interval string) bool {
+	if len(c.Intervals) == 0 {
+		return true
+	}
+	for _, i := range c.Intervals {
+		if i == interval {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildAll creates every enabled strategy in cfgs
+func (r *Registry) BuildAll(cfgs []config.StrategyConfig) ([]Configured, error) {
+	var built []Configured
+	for i, cfg := range cfgs {
+		if !cfg.IsEnabled() {
+			continue
+		}
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("strategies[%d]: missing name", i)
+		}
+		for _, interval := range cfg.Intervals {
+			if _, err := types.ParseInterval(interval); err != nil {
+				return nil, fmt.Errorf("strategy %q: %w", cfg.Name, err)
+			}
+		}
+
+		confluence, err := checkConfluence(cfg.Confluence)
+		if err != nil {
+			return nil, fmt.Errorf("strategy %q: %w", cfg.Name, err)
+		}
+
+		matcher, err := r.Build(cfg.Name, cfg.Params)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Transform.Type != "" {
+			t, err := checkTransform(cfg.Transform, matcher)
+			if err != nil {
+				return nil, fmt.Errorf("strategy %q: %w", cfg.Name, err)
+			}
+			matcher = NewTransformed(matcher, t)
+		}
+		built = append(built, Configured{Name: cfg.Name, Matcher: matcher, Intervals: cfg.Intervals, Confluence: confluence})
+	}
+	return built, nil
+}
+
+// checkConfluence validates a confluence config and fills the EMA period
+func checkConfluence(cfg config.ConfluenceConfig) (config.ConfluenceConfig, error) {
+	switch cfg.Mode {
+	case "":
+		return cfg, nil
+	case ConfluenceTag, ConfluenceRequire:
+	default:
+		return cfg, fmt.Errorf("confluence mode must be %q or %q, got %q", ConfluenceTag, ConfluenceRequire, cfg.Mode)
+	}
+
+	if cfg.Interval != "" {
+		if _, err := types.ParseInterval(cfg.Interval); err != nil {
+			return cfg, fmt.Errorf("confluence: %w", err)
+		}
+	}
+	if cfg.EMAPeriod == 0 {
+		cfg.EMAPeriod = 50
+	}
+	if cfg.EMAPeriod < 1 {
+		return cfg, fmt.Errorf("confluence emaPeriod must be at least 1, got %d", cfg.EMAPeriod)
+	}
+	return cfg, nil
+}
+
+// checkTransform validates a transform config and fills the Renko defaults
+func checkTransform(cfg config.TransformConfig, matcher types.PatternMatcher) (config.TransformConfig, error) {
+	switch cfg.Type {
+	case transform.HeikinAshiType:
+		return cfg, nil
+	case transform.RenkoType:
+	default:
+		return cfg, fmt.Errorf("transform type must be %q or %q, got %q", transform.HeikinAshiType, transform.RenkoType, cfg.Type)
+	}
+
+	if cfg.BrickATR == 0 {
+		cfg.BrickATR = 1
+	}
+	if cfg.ATRPeriod == 0 {
+		cfg.ATRPeriod = 14
+	}
+	if cfg.History == 0 {
+		cfg.History = cfg.ATRPeriod + 10*matcher.GetRequiredCandles()
+	}
+	if cfg.Brick < 0 || cfg.BrickATR < 0 || cfg.ATRPeriod < 1 || cfg.History <= cfg.ATRPeriod {
+		return cfg, fmt.Errorf("transform brick and brickATR must be positive, atrPeriod at least 1 and history longer than atrPeriod")
+	}
+	return cfg, nil
+}
+
+// ConfluenceInterval is the higher timeframe checked for signals on interval,
+// or "" when confluence is off
+func (c Configured) ConfluenceInterval(interval string) string {
+	if c.Confluence.Mode == "" {
+		return ""
+	}
+	if c.Confluence.Interval != "" {
+		return c.Confluence.Interval
+	}
+	return types.HigherInterval(interval)
+}
+
+// Params gives factories typed access to configured parameters. Names are
+// matched case-insensitively because viper lower-cases config keys. The first
+// conversion error is kept and reported by Build.
+type Params struct {
+	values map[string]interface{} // keyed by lower-cased name
+	names  map[string]string      // lower-cased name -> name as written
+	used   map[string]string      // lower-cased name -> name as read by the factory
+	err    error
+}
+
+func newParams(values map[string]interface{}) *Params {
+	p := &Params{
+		values: make(map[string]interface{}, len(values)),
+		names:  make(map[string]string, len(values)),
+		used:   make(map[string]string),
+	}
+	for k, v := range values {
+		p.values[strings.ToLower(k)] = v
+		p.names[strings.ToLower(k)] = k
+	}
+	return p
+}
+
+func (p *Params) lookup(name string) (interface{}, bool) {
+	key := strings.ToLower(name)
+	p.used[key] = name
+	v, ok := p.values[key]
+	return v, ok
+}
+
+func (p *Params) fail(name string, v interface{}, kind string) {
+	if p.err == nil {
+		p.err = fmt.Errorf("parameter %s must be %s, got %v", name, kind, v)
+	}
+}
+
+// Float returns the named number, or def when it is not set
+func (p *Params) Float(name string, def float64) float64 {
+	v, ok := p.lookup(name)
+	if !ok {
+		return def
+	}
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+	p.fail(name, v, "a number")
+	return def
+}
+
+// Int returns the named whole number, or def when it is not set
+func (p *Params) Int(name string, def int) int {
+	v, ok := p.lookup(name)
+	if !ok {
+		return def
+	}
+	f := p.Float(name, float64(def))
+	if f != math.Trunc(f) {
+		p.fail(name, v, "a whole number")
+		return def
+	}
+	return int(f)
+}
+
+// String returns the named string, or def when it is not set
+func (p *Params) String(name, def string) string {
+	v, ok := p.lookup(name)
+	if !ok {
+		return def
+	}
+	s, ok := v.(string)
+	if !ok {
+		p.fail(name, v, "a string")
+		return def
+	}
+	return s
+}
+
+func (p *Params) checkUnused() error {
+	var unknown, known []string
+	for key := range p.values {
+		if _, ok := p.used[key]; !ok {
+			unknown = append(unknown, p.names[key])
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	for _, name := range p.used {
+		known = append(known, name)
+	}
+	sort.Strings(unknown)
+	sort.Strings(known)
+	return fmt.Errorf("unknown parameter(s) %s (accepted: %s)", strings.Join(unknown, ", "), strings.Join(known, ", "))
+}
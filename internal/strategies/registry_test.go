@@ -0,0 +1,136 @@
+package strategies
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/config"
+)
+
+func TestRegistry_Build(t *testing.T) {
+	registry := DefaultRegistry()
+
+	tests := []struct {
+		name    string
+		params  map[string]interface{}
+		wantErr string
+	}{
+		{name: "consecutive", params: map[string]interface{}{"minCount": 5}},
+		{name: "consecutive", params: map[string]interface{}{"mincount": 5}}, // as viper delivers it
+		{name: "reversal"},
+		{name: "reversal", params: map[string]interface{}{"minBodyPct": 10, "minBodyATR": 0.1, "atrPeriod": 20}},
+		{name: "consecutive", params: map[string]interface{}{"minBodyPct": 150}, wantErr: "minBodyPct must be 0-100"},
+		{name: "volumeSpike", params: map[string]interface{}{"period": 30, "multiple": 0, "zScore": 3, "minChange": 1.5}},
+		{name: "volumeSpike", params: map[string]interface{}{"multiple": 0}, wantErr: "multiple or zScore"},
+		{name: "breakout", params: map[string]interface{}{"lookback": 50, "confirm": 1, "minATR": 0.5}},
+		{name: "breakout", params: map[string]interface{}{"confirm": -1}, wantErr: "must not be negative"},
+		{name: "nearLevel", params: map[string]interface{}{"lookback": 300, "width": 0.25, "bins": 0}},
+		{name: "nearLevel", params: map[string]interface{}{"lookback": 5}, wantErr: "lookback must cover"},
+		{name: "nearLevel", params: map[string]interface{}{"lookback": 14, "atrPeriod": 14}, wantErr: "lookback must cover"},
+		{name: "nearLevel", params: map[string]interface{}{"lookback": 15, "atrPeriod": 14}},
+		{name: "divergence", params: map[string]interface{}{"oscillator": "macd", "kind": "hidden", "window": 80}},
+		{name: "divergence", params: map[string]interface{}{"oscillator": "stoch"}, wantErr: "oscillator must be"},
+		{name: "divergence", params: map[string]interface{}{"kind": "any"}, wantErr: "kind must be"},
+		{name: "engulfing", params: map[string]interface{}{"trendBars": 4, "dojiBody": 0.05}},
+		{name: "rule", params: map[string]interface{}{"expr": "rsi(14) < 30", "label": "OVERSOLD", "direction": "bullish"}},
+		{name: "rule", wantErr: "missing parameter expr"},
+		{name: "rule", params: map[string]interface{}{"expr": "rsi(14) <"}, wantErr: "column 10"},
+		{name: "nope", wantErr: "unknown strategy"},
+		{name: "consecutive", params: map[string]interface{}{"minCnt": 5}, wantErr: "unknown parameter(s) minCnt"},
+		{name: "consecutive", params: map[string]interface{}{"minCount": 2.5}, wantErr: "whole number"},
+		{name: "consecutive", params: map[string]interface{}{"minCount": "five"}, wantErr: "a number"},
+		{name: "consecutive", params: map[string]interface{}{"minCount": 0}, wantErr: "at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := registry.Build(tt.name, tt.params)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Build() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if strategy == nil {
+				t.Fatal("Build() returned nil strategy")
+			}
+		})
+	}
+}
+
+func TestRegistry_BuildParams(t *testing.T) {
+	strategy, err := DefaultRegistry().Build("consecutive", map[string]interface{}{"minCount": 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strategy.(*ConsecutiveCandles).MinCount; got != 5 {
+		t.Errorf("MinCount = %d, want 5", got)
+	}
+
+	strategy, err = DefaultRegistry().Build("hammer", map[string]interface{}{"trendBars": 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strategy.GetRequiredCandles(); got != 6 {
+		t.Errorf("GetRequiredCandles() = %d, want 6", got)
+	}
+}
+
+func TestRegistry_BuildAll(t *testing.T) {
+	disabled := false
+	built, err := DefaultRegistry().BuildAll([]config.StrategyConfig{
+		{Name: "consecutive", Intervals: []string{"4h"}},
+		{Name: "reversal", Enabled: &disabled},
+		{Name: "doji"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(built) != 2 {
+		t.Fatalf("built %d strategies, want 2", len(built))
+	}
+	if built[0].RunsOn("1h") || !built[0].RunsOn("4h") {
+		t.Errorf("consecutive should only run on 4h")
+	}
+	if !built[1].RunsOn("1h") {
+		t.Errorf("doji should run on every interval")
+	}
+
+	if _, err := DefaultRegistry().BuildAll([]config.StrategyConfig{{Name: "doji", Intervals: []string{"7x"}}}); err == nil {
+		t.Error("expected an error for an unknown interval")
+	}
+	if _, err := DefaultRegistry().BuildAll([]config.StrategyConfig{{}}); err == nil {
+		t.Error("expected an error for a missing name")
+	}
+
+	if _, err := DefaultRegistry().BuildAll([]config.StrategyConfig{{Name: "doji", Confluence: config.ConfluenceConfig{Mode: "maybe"}}}); err == nil {
+		t.Error("expected an error for an unknown confluence mode")
+	}
+}
+
+func TestConfigured_Confluence(t *testing.T) {
+	built, err := DefaultRegistry().BuildAll([]config.StrategyConfig{
+		{Name: "reversal", Confluence: config.ConfluenceConfig{Mode: ConfluenceRequire}},
+		{Name: "doji", Confluence: config.ConfluenceConfig{Mode: ConfluenceTag, Interval: "1d", EMAPeriod: 20}},
+		{Name: "hammer"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := built[0].ConfluenceInterval("1h"); got != "4h" {
+		t.Errorf("default confluence interval = %q, want 4h", got)
+	}
+	if built[0].Confluence.EMAPeriod != 50 {
+		t.Errorf("default EMA period = %d, want 50", built[0].Confluence.EMAPeriod)
+	}
+	if got := built[1].ConfluenceInterval("1h"); got != "1d" {
+		t.Errorf("configured confluence interval = %q, want 1d", got)
+	}
+	if got := built[2].ConfluenceInterval("1h"); got != "" {
+		t.Errorf("confluence interval without a mode = %q, want \"\"", got)
+	}
+}
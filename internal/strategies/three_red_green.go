@@ -2,36 +2,71 @@ package strategies
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/letieu/trade-bot/internal/types"
 )
 
-type ThreeCandleReversal struct{}
+type ThreeCandleReversal struct {
+	Count      int              // Length of the same-colour run before the reversal candle
+	Classifier CandleClassifier // Neutral candles neither form the run nor reverse it
+}
 
 func NewThreeCandleReversal() *ThreeCandleReversal {
-	return &ThreeCandleReversal{}
+	return NewCandleReversal(3)
+}
+
+func NewCandleReversal(count int) *ThreeCandleReversal {
+	return &ThreeCandleReversal{Count: count}
 }
 
-func (s *ThreeCandleReversal) Match(candles []types.Candle) (bool, error) {
-	if len(candles) < 4 {
-		return false, fmt.Errorf("need at least 4 candles, got %d", len(candles))
+// Match reports the direction of the reversal candle. Confidence is the
+// reversal body relative to the average body of the run, capped at 1.
+func (s *ThreeCandleReversal) Match(candles []types.Candle) (*types.MatchResult, error) {
+	if len(candles) < s.Count+1 {
+		return nil, fmt.Errorf("need at least %d candles, got %d", s.Count+1, len(candles))
 	}
 
-	lastFour := candles[len(candles)-4:]
+	window := candles[len(candles)-s.Count-1:]
 
-	colors := make([]types.CandleColor, 4)
-	for i, candle := range lastFour {
-		colors[i] = candle.Color()
+	// Classify the whole series so the ATR minimum has its history
+	colors := s.Classifier.Classify(candles)
+	colors = colors[len(colors)-len(window):]
+
+	last := len(colors) - 1
+	if colors[0] == types.ColorNeutral {
+		return nil, nil
+	}
+	for i := 1; i < last; i++ {
+		if colors[i] != colors[0] {
+			return nil, nil
+		}
 	}
 
-	firstThreeSame := colors[0] == colors[1] && colors[1] == colors[2]
-	lastIsOpposite := colors[3] != colors[2]
+	lastIsOpposite := colors[last] != colors[last-1] && colors[last] != types.ColorNeutral
+	if !lastIsOpposite {
+		return nil, nil
+	}
 
-	if !firstThreeSame || !lastIsOpposite {
-		return false, nil
+	direction := types.Bullish
+	if colors[last] == types.ColorRed {
+		direction = types.Bearish
 	}
 
-	return true, nil
+	runBody := 0.0
+	for _, candle := range window[:last] {
+		runBody += body(candle)
+	}
+	confidence := 1.0
+	if runBody > 0 {
+		confidence = math.Min(1, body(window[last])/(runBody/float64(last)))
+	}
+
+	return &types.MatchResult{
+		Direction:  direction,
+		Confidence: confidence,
+		Candles:    window,
+	}, nil
 }
 
 func (s *ThreeCandleReversal) GetName() string {
@@ -39,13 +74,9 @@ func (s *ThreeCandleReversal) GetName() string {
 }
 
 func (s *ThreeCandleReversal) GetDescription() string {
-	return "Detects 3 consecutive candles of the same color followed by an opposite color candle"
+	return fmt.Sprintf("Detects %d consecutive candles of the same color followed by an opposite color candle", s.Count)
 }
 
 func (s *ThreeCandleReversal) GetRequiredCandles() int {
-	return 5
-}
-
-func (s *ThreeCandleReversal) GetMetadata(candles []types.Candle) map[string]interface{} {
-	return map[string]interface{}{} // No additional metadata for this pattern
+	return s.Count + 2 + s.Classifier.Warmup()
 }
@@ -76,13 +76,38 @@ func TestThreeCandleReversal_Match(t *testing.T) {
 					t.Errorf("Match() error = %v, wantErr %v", err, false)
 				}
 			}
-			if got != tt.wantMatch {
+			if (got != nil) != tt.wantMatch {
 				t.Errorf("Match() = %v, want %v", got, tt.wantMatch)
 			}
 		})
 	}
 }
 
+func TestThreeCandleReversal_Direction(t *testing.T) {
+	strategy := NewThreeCandleReversal()
+
+	bullish, _ := strategy.Match([]types.Candle{
+		createCandle(100, 90), createCandle(90, 80), createCandle(80, 70), createCandle(70, 75),
+	})
+	if bullish == nil || bullish.Direction != types.Bullish {
+		t.Fatalf("Match() = %+v, want a bullish reversal", bullish)
+	}
+	// Reversal body 5 against an average run body of 10
+	if bullish.Confidence != 0.5 {
+		t.Errorf("Confidence = %v, want 0.5", bullish.Confidence)
+	}
+	if len(bullish.Candles) != 4 {
+		t.Errorf("pattern has %d candles, want 4", len(bullish.Candles))
+	}
+
+	bearish, _ := strategy.Match([]types.Candle{
+		createCandle(70, 80), createCandle(80, 90), createCandle(90, 100), createCandle(100, 80),
+	})
+	if bearish == nil || bearish.Direction != types.Bearish || bearish.Confidence != 1 {
+		t.Errorf("Match() = %+v, want a bearish reversal with confidence 1", bearish)
+	}
+}
+
 func createCandle(open, close float64) types.Candle {
 	return types.Candle{
 		Timestamp: time.Now().Unix(),
@@ -0,0 +1,140 @@
+package strategies
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/indicators"
+	"github.com/letieu/trade-bot/internal/transform"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// heikinAshiWarmup is the extra candles fetched for Heikin-Ashi opens to settle
+const heikinAshiWarmup = 10
+
+// Transformed runs a strategy on Heikin-Ashi or Renko bars instead of the raw
+// candles. Matches are reported on the raw candles, so signal prices stay real.
+type Transformed struct {
+	Matcher types.PatternMatcher
+	Config  config.TransformConfig
+}
+
+func NewTransformed(matcher types.PatternMatcher, cfg config.TransformConfig) *Transformed {
+	return &Transformed{Matcher: matcher, Config: cfg}
+}
+
+// Match converts candles and matches the bars. Renko bricks only count when
+// the last candle formed a brick, so a pattern is reported once.
+func (s *Transformed) Match(candles []types.Candle) (*types.MatchResult, error) {
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("need at least 1 candle, got 0")
+	}
+
+	var bars []types.Candle
+	switch s.Config.Type {
+	case transform.HeikinAshiType:
+		bars = transform.HeikinAshi(candles)
+	case transform.RenkoType:
+		// Bricks depend on where they start, so always draw them from the same
+		// anchor whatever window the caller passes
+		candles = s.anchored(candles)
+		bars = transform.Renko(candles, s.brickSize(candles))
+		if len(bars) == 0 || bars[len(bars)-1].Timestamp != candles[len(candles)-1].Timestamp {
+			return nil, nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown transform %q", s.Config.Type)
+	}
+
+	if len(bars) < s.Matcher.GetRequiredCandles() {
+		// Too few bricks: the market has not moved enough yet
+		return nil, nil
+	}
+
+	match, err := s.Matcher.Match(bars)
+	if match == nil || err != nil {
+		return match, err
+	}
+
+	// Swap the bars for the raw candles they span
+	first := match.Candles[0].Timestamp
+	for i := range candles {
+		if candles[i].Timestamp >= first {
+			match.Candles = candles[i:]
+			break
+		}
+	}
+
+	metadata := map[string]interface{}{"transform": s.Config.Type}
+	for k, v := range match.Metadata {
+		metadata[k] = v
+	}
+	match.Metadata = metadata
+	return match, nil
+}
+
+// anchored returns candles from the Renko anchor on, or nil when they do not
+// reach back to it. The anchor is the start of the block of History candles
+// before the one holding the last candle, with blocks aligned to Unix time, so
+// it only moves once every History candles and is the same for a live scan
+// and a backtest at the same candle.
+func (s *Transformed) anchored(candles []types.Candle) []types.Candle {
+	if len(candles) < 2 {
+		return nil
+	}
+	last := candles[len(candles)-1]
+	step, err := types.ParseInterval(last.Interval)
+	if err != nil {
+		step = time.Duration(last.Timestamp-candles[len(candles)-2].Timestamp) * time.Millisecond
+	}
+	block := int64(s.Config.History) * step.Milliseconds()
+	if block <= 0 {
+		return nil
+	}
+
+	anchor := (last.Timestamp/block - 1) * block
+	if candles[0].Timestamp > anchor {
+		return nil
+	}
+	for i, c := range candles {
+		if c.Timestamp >= anchor {
+			return candles[i:]
+		}
+	}
+	return nil
+}
+
+// brickSize is the configured Brick, or BrickATR times the ATR over the first
+// candles from the anchor, so appending a candle never redraws the bricks
+// already formed. It is 0 while there are too few candles for the ATR.
+func (s *Transformed) brickSize(candles []types.Candle) float64 {
+	if s.Config.Brick > 0 {
+		return s.Config.Brick
+	}
+	if len(candles) <= s.Config.ATRPeriod {
+		return 0
+	}
+	atr := indicators.ATRSeries(candles[:s.Config.ATRPeriod+1], s.Config.ATRPeriod)
+	return s.Config.BrickATR * atr[s.Config.ATRPeriod]
+}
+
+func (s *Transformed) GetName() string {
+	if s.Config.Type == transform.RenkoType {
+		return s.Matcher.GetName() + " (RENKO)"
+	}
+	return s.Matcher.GetName() + " (HA)"
+}
+
+func (s *Transformed) GetDescription() string {
+	return fmt.Sprintf("%s, on %s bars", s.Matcher.GetDescription(), s.Config.Type)
+}
+
+// GetRequiredCandles adds the Heikin-Ashi warm-up, or for Renko covers the
+// two blocks of history back to the anchor since bricks form at an unknown rate
+func (s *Transformed) GetRequiredCandles() int {
+	if s.Config.Type == transform.RenkoType {
+		return 2 * s.Config.History
+	}
+	return s.Matcher.GetRequiredCandles() + heikinAshiWarmup
+}
@@ -0,0 +1,155 @@
+package strategies
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/transform"
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// noisyRally rises steadily but every third candle closes slightly red
+func noisyRally(n int) []types.Candle {
+	candles := make([]types.Candle, n)
+	price := 100.0
+	for i := range candles {
+		if i%3 == 2 {
+			candles[i] = ohlc(price+1, price+1.5, price-0.5, price)
+		} else {
+			candles[i] = ohlc(price, price+3.5, price-0.5, price+3)
+			price += 3
+		}
+		candles[i].Timestamp = int64(i)
+	}
+	return candles
+}
+
+func TestTransformed_HeikinAshi(t *testing.T) {
+	candles := noisyRally(20)
+	consecutive := NewConsecutiveCandles(5)
+
+	if match, _ := consecutive.Match(candles); match != nil {
+		t.Fatalf("raw candles matched %+v, want the red candles to break the run", match)
+	}
+
+	ha := NewTransformed(consecutive, config.TransformConfig{Type: "heikinAshi"})
+	match, err := ha.Match(candles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match == nil {
+		t.Fatal("expected a green run on Heikin-Ashi bars")
+	}
+	if match.Direction != types.Bullish || match.Metadata["transform"] != "heikinAshi" {
+		t.Errorf("got %s %v, want bullish on heikinAshi", match.Direction, match.Metadata["transform"])
+	}
+	// The pattern is reported on the raw candles
+	last := match.Candles[len(match.Candles)-1]
+	if last != candles[len(candles)-1] {
+		t.Errorf("last pattern candle = %+v, want the raw last candle", last)
+	}
+
+	if got := ha.GetName(); got != consecutive.GetName()+" (HA)" {
+		t.Errorf("GetName() = %q", got)
+	}
+	if got := ha.GetRequiredCandles(); got != consecutive.GetRequiredCandles()+heikinAshiWarmup {
+		t.Errorf("GetRequiredCandles() = %d", got)
+	}
+}
+
+func TestTransformed_Renko(t *testing.T) {
+	candles := noisyRally(80)
+	renko := NewTransformed(NewConsecutiveCandles(3), config.TransformConfig{Type: "renko", BrickATR: 1, ATRPeriod: 5, History: 40})
+
+	match, err := renko.Match(candles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match == nil {
+		t.Fatal("expected a run of up bricks")
+	}
+	if match.Direction != types.Bullish {
+		t.Errorf("direction = %s, want bullish", match.Direction)
+	}
+
+	// The last candle forms no brick: nothing new to report
+	if match, _ := renko.Match(candles[:len(candles)-1]); match != nil {
+		t.Errorf("got %+v without a new brick, want no match", match)
+	}
+
+	// Candles that do not reach back to the anchor draw nothing
+	if match, _ := renko.Match(candles[len(candles)-40:]); match != nil {
+		t.Errorf("got %+v without the anchor, want no match", match)
+	}
+}
+
+func TestTransformed_RenkoBricksAreStable(t *testing.T) {
+	// A choppy rally whose candles keep widening, so the ATR grows bar by bar
+	candles := make([]types.Candle, 200)
+	for i := range candles {
+		price := 100 + float64(i)*0.5 + 8*math.Sin(float64(i)*0.3)
+		spread := 1 + float64(i)*0.01
+		candles[i] = types.Candle{Timestamp: int64(i), Open: price - 1, High: price + spread, Low: price - spread, Close: price}
+	}
+	renko := NewTransformed(NewConsecutiveCandles(3), config.TransformConfig{Type: "renko", BrickATR: 1, ATRPeriod: 5, History: 20})
+	required := renko.GetRequiredCandles()
+
+	bricks := func(window []types.Candle) []types.Candle {
+		anchored := renko.anchored(window)
+		return transform.Renko(anchored, renko.brickSize(anchored))
+	}
+
+	// Slide a window of the required length far past the history: while the
+	// anchor stays put, a new candle only adds bricks
+	anchors := 0
+	for end := required; end < len(candles); end++ {
+		before := bricks(candles[end-required : end])
+		after := bricks(candles[end-required+1 : end+1])
+		if len(before) == 0 || len(after) == 0 {
+			t.Fatalf("no bricks in the window ending at candle %d", end)
+		}
+
+		// Any window reaching back to the anchor gives the same match, so
+		// backtests over the full series agree with live scans
+		want, _ := renko.Match(candles[end-required+1 : end+1])
+		got, _ := renko.Match(candles[:end+1])
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Match() over the full series at candle %d = %+v, want %+v", end, got, want)
+		}
+
+		if renko.anchored(candles[end-required+1 : end+1])[0].Timestamp != renko.anchored(candles[end-required : end])[0].Timestamp {
+			anchors++
+			if end%renko.Config.History != 0 {
+				t.Errorf("anchor moved at candle %d, want only every %d candles", end, renko.Config.History)
+			}
+			continue
+		}
+		if len(after) < len(before) || !reflect.DeepEqual(after[:len(before)], before) {
+			t.Fatalf("appending candle %d redrew the bricks", end)
+		}
+	}
+	if anchors < 5 {
+		t.Errorf("anchor moved %d times, want the window to slide well past the history", anchors)
+	}
+}
+
+func TestRegistry_BuildAllTransform(t *testing.T) {
+	built, err := DefaultRegistry().BuildAll([]config.StrategyConfig{
+		{Name: "consecutive", Transform: config.TransformConfig{Type: "renko"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	renko := built[0].Matcher.(*Transformed)
+	if renko.Config.BrickATR != 1 || renko.Config.ATRPeriod != 14 || renko.Config.History != 14+10*4 {
+		t.Errorf("renko defaults = %+v", renko.Config)
+	}
+
+	if _, err := DefaultRegistry().BuildAll([]config.StrategyConfig{
+		{Name: "consecutive", Transform: config.TransformConfig{Type: "kagi"}},
+	}); err == nil {
+		t.Error("expected an error for an unknown transform")
+	}
+}
@@ -0,0 +1,109 @@
+package strategies
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// VolumeSpike flags a candle whose volume stands out from the Period candles
+// before it, either as a multiple of their mean or as a z-score
+type VolumeSpike struct {
+	Period    int     // Candles in the rolling baseline
+	Multiple  float64 // Minimum volume / mean, 0 disables
+	ZScore    float64 // Minimum (volume - mean) / stddev, 0 disables
+	MinChange float64 // Minimum open-to-close move in percent, 0 disables
+}
+
+func NewVolumeSpike(period int, multiple, zScore, minChange float64) *VolumeSpike {
+	return &VolumeSpike{Period: period, Multiple: multiple, ZScore: zScore, MinChange: minChange}
+}
+
+// Match reports the colour of the spike candle as its direction. It matches
+// when either enabled threshold is reached. Confidence is the multiple or
+// z-score that matched against twice its threshold, capped at 1.
+func (s *VolumeSpike) Match(candles []types.Candle) (*types.MatchResult, error) {
+	if len(candles) < s.Period+1 {
+		return nil, fmt.Errorf("need at least %d candles, got %d", s.Period+1, len(candles))
+	}
+
+	last := candles[len(candles)-1]
+	baseline := candles[len(candles)-s.Period-1 : len(candles)-1]
+
+	mean := 0.0
+	for _, candle := range baseline {
+		mean += candle.Volume
+	}
+	mean /= float64(len(baseline))
+	if mean <= 0 {
+		return nil, nil
+	}
+
+	variance := 0.0
+	for _, candle := range baseline {
+		variance += (candle.Volume - mean) * (candle.Volume - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(baseline)))
+
+	multiple := last.Volume / mean
+	zScore := 0.0
+	if stddev > 0 {
+		zScore = (last.Volume - mean) / stddev
+	}
+
+	byMultiple := s.Multiple > 0 && multiple >= s.Multiple
+	// A flat baseline has no z-score, so any rise above it counts
+	byZScore := s.ZScore > 0 && (zScore >= s.ZScore || (stddev == 0 && last.Volume > mean))
+	if !byMultiple && !byZScore {
+		return nil, nil
+	}
+
+	change := 0.0
+	if last.Open > 0 {
+		change = (last.Close - last.Open) / last.Open * 100
+	}
+	if math.Abs(change) < s.MinChange {
+		return nil, nil
+	}
+
+	direction := types.Bullish
+	if last.Color() == types.ColorRed {
+		direction = types.Bearish
+	}
+
+	confidence := 0.0
+	if byMultiple {
+		confidence = multiple / (2 * s.Multiple)
+	}
+	if byZScore {
+		zConfidence := 1.0
+		if stddev > 0 {
+			zConfidence = zScore / (2 * s.ZScore)
+		}
+		confidence = math.Max(confidence, zConfidence)
+	}
+
+	return &types.MatchResult{
+		Direction:  direction,
+		Confidence: math.Min(1, confidence),
+		Candles:    candles[len(candles)-1:],
+		Metadata: map[string]interface{}{
+			"volume_multiple": multiple,
+			"volume_zscore":   zScore,
+			"price_change":    change,
+		},
+	}, nil
+}
+
+func (s *VolumeSpike) GetName() string {
+	return "ĐỘT BIẾN KHỐI LƯỢNG"
+}
+
+func (s *VolumeSpike) GetDescription() string {
+	return fmt.Sprintf("Detects volume above %.1fx or %.1f standard deviations over the %d-candle mean", s.Multiple, s.ZScore, s.Period)
+}
+
+func (s *VolumeSpike) GetRequiredCandles() int {
+	return s.Period + 1
+}
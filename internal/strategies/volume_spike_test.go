@@ -0,0 +1,86 @@
+package strategies
+
+import (
+	"testing"
+	"time"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// withVolumes builds flat candles with the given volumes; the last one opens
+// at 100 and closes at lastClose
+func withVolumes(lastClose float64, volumes ...float64) []types.Candle {
+	candles := make([]types.Candle, len(volumes))
+	for i, volume := range volumes {
+		candles[i] = types.Candle{Open: 100, High: 101, Low: 99, Close: 100.5, Volume: volume}
+	}
+	last := &candles[len(candles)-1]
+	last.Close = lastClose
+	last.High = max(last.High, lastClose)
+	last.Low = min(last.Low, lastClose)
+	return candles
+}
+
+func TestVolumeSpike(t *testing.T) {
+	tests := []struct {
+		name          string
+		strategy      *VolumeSpike
+		candles       []types.Candle
+		wantDirection string // "" means no match
+		wantMultiple  float64
+	}{
+		{"multiple reached", NewVolumeSpike(4, 3, 0, 0), withVolumes(102, 100, 100, 100, 100, 420), types.Bullish, 4.2},
+		{"bearish spike", NewVolumeSpike(4, 3, 0, 0), withVolumes(97, 100, 100, 100, 100, 300), types.Bearish, 3},
+		{"below multiple", NewVolumeSpike(4, 3, 0, 0), withVolumes(102, 100, 100, 100, 100, 290), "", 0},
+		{"z-score reached", NewVolumeSpike(4, 0, 2, 0), withVolumes(102, 90, 110, 90, 110, 140), types.Bullish, 1.4},
+		{"z-score not reached", NewVolumeSpike(4, 0, 2, 0), withVolumes(102, 90, 110, 90, 110, 115), "", 0},
+		{"z-score on flat baseline", NewVolumeSpike(4, 0, 2, 0), withVolumes(102, 100, 100, 100, 100, 101), types.Bullish, 1.01},
+		{"either threshold", NewVolumeSpike(4, 5, 2, 0), withVolumes(102, 90, 110, 90, 110, 140), types.Bullish, 1.4},
+		{"price change too small", NewVolumeSpike(4, 3, 0, 1), withVolumes(100.5, 100, 100, 100, 100, 500), "", 0},
+		{"price change large enough", NewVolumeSpike(4, 3, 0, 1), withVolumes(98, 100, 100, 100, 100, 500), types.Bearish, 5},
+		{"no volume", NewVolumeSpike(4, 3, 0, 0), withVolumes(102, 0, 0, 0, 0, 100), "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := tt.strategy.Match(tt.candles)
+			if err != nil {
+				t.Fatalf("Match() error = %v", err)
+			}
+			if (match != nil) != (tt.wantDirection != "") {
+				t.Fatalf("Match() = %+v, want direction %q", match, tt.wantDirection)
+			}
+			if match == nil {
+				return
+			}
+
+			if match.Direction != tt.wantDirection {
+				t.Errorf("direction = %q, want %q", match.Direction, tt.wantDirection)
+			}
+			if got := match.Metadata["volume_multiple"].(float64); got < tt.wantMultiple-1e-9 || got > tt.wantMultiple+1e-9 {
+				t.Errorf("volume_multiple = %v, want %v", got, tt.wantMultiple)
+			}
+			if match.Confidence <= 0 || match.Confidence > 1 {
+				t.Errorf("Confidence = %v, want (0, 1]", match.Confidence)
+			}
+		})
+	}
+}
+
+func TestVolumeSpike_RequiredCandles(t *testing.T) {
+	spike := NewVolumeSpike(20, 3, 0, 0)
+	if got := spike.GetRequiredCandles(); got != 21 {
+		t.Errorf("GetRequiredCandles() = %d, want 21", got)
+	}
+	if _, err := spike.Match(withVolumes(102, 100, 300)); err == nil {
+		t.Error("expected an error without a full baseline")
+	}
+}
+
+func TestVolumeSpike_Signal(t *testing.T) {
+	match, _ := NewVolumeSpike(4, 3, 0, 0).Match(withVolumes(102, 100, 100, 100, 100, 420))
+	signal := types.NewSignal("BTCUSDT", "1h", "spike", match, time.Now())
+	if signal.VolumeMultiple < 4.19 || signal.VolumeMultiple > 4.21 {
+		t.Errorf("VolumeMultiple = %v, want 4.2", signal.VolumeMultiple)
+	}
+}
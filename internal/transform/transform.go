@@ -0,0 +1,77 @@
+// Package transform converts candles into alternative bar series before they
+// reach a PatternMatcher:
+//   - Heikin-Ashi: averaged candles, one per input candle, whose colour runs
+//     are far less noisy than raw OHLC
+//   - Renko: fixed-size bricks that only form when the close moves a full
+//     brick, so time and small moves drop out
+package transform
+
+import (
+	"math"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+// Transform types
+const (
+	HeikinAshiType = "heikinAshi"
+	RenkoType      = "renko"
+)
+
+// HeikinAshi returns the Heikin-Ashi candle for every candle. The first open
+// is the midpoint of the first body; later ones settle within a few candles.
+func HeikinAshi(candles []types.Candle) []types.Candle {
+	out := make([]types.Candle, len(candles))
+	for i, c := range candles {
+		ha := c
+		ha.Close = (c.Open + c.High + c.Low + c.Close) / 4
+		if i == 0 {
+			ha.Open = (c.Open + c.Close) / 2
+		} else {
+			ha.Open = (out[i-1].Open + out[i-1].Close) / 2
+		}
+		ha.High = math.Max(c.High, math.Max(ha.Open, ha.Close))
+		ha.Low = math.Min(c.Low, math.Min(ha.Open, ha.Close))
+		out[i] = ha
+	}
+	return out
+}
+
+// Renko returns the bricks of size brick formed by the closes of candles,
+// starting from the first close rounded to a multiple of brick, so series that
+// start on different candles share brick levels. A brick in the direction of the last one
+// needs a move of one brick, a reversal two. Each brick carries the
+// timestamp of the candle that formed it, and the first brick a candle forms
+// the volume traded since the previous brick.
+func Renko(candles []types.Candle, brick float64) []types.Candle {
+	if len(candles) == 0 || brick <= 0 {
+		return nil
+	}
+
+	var bricks []types.Candle
+	origin := math.Round(candles[0].Close/brick) * brick
+	top, bottom := origin, origin
+	volume := 0.0
+	for _, c := range candles[1:] {
+		volume += c.Volume
+		for {
+			var b types.Candle
+			if c.Close >= top+brick {
+				b = types.Candle{Open: top, Close: top + brick}
+				bottom, top = top, top+brick
+			} else if c.Close <= bottom-brick {
+				b = types.Candle{Open: bottom, Close: bottom - brick}
+				top, bottom = bottom, bottom-brick
+			} else {
+				break
+			}
+
+			b.Timestamp, b.Symbol, b.Interval = c.Timestamp, c.Symbol, c.Interval
+			b.High = math.Max(b.Open, b.Close)
+			b.Low = math.Min(b.Open, b.Close)
+			b.Volume, volume = volume, 0
+			bricks = append(bricks, b)
+		}
+	}
+	return bricks
+}
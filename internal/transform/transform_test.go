@@ -0,0 +1,73 @@
+package transform
+
+import (
+	"math"
+	"testing"
+
+	"github.com/letieu/trade-bot/internal/types"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestHeikinAshi(t *testing.T) {
+	candles := []types.Candle{
+		{Timestamp: 1, Open: 10, High: 14, Low: 9, Close: 12, Volume: 5},
+		{Timestamp: 2, Open: 12, High: 13, Low: 8, Close: 9, Volume: 7},
+	}
+
+	ha := HeikinAshi(candles)
+	if len(ha) != 2 {
+		t.Fatalf("got %d candles, want 2", len(ha))
+	}
+
+	// First: close (10+14+9+12)/4, open the body midpoint
+	if !near(ha[0].Close, 11.25) || !near(ha[0].Open, 11) || ha[0].High != 14 || ha[0].Low != 9 {
+		t.Errorf("first = %+v, want O 11 H 14 L 9 C 11.25", ha[0])
+	}
+	// Second: open the midpoint of the previous Heikin-Ashi body
+	if !near(ha[1].Close, 10.5) || !near(ha[1].Open, 11.125) || ha[1].High != 13 || ha[1].Low != 8 {
+		t.Errorf("second = %+v, want O 11.125 H 13 L 8 C 10.5", ha[1])
+	}
+	if ha[1].Timestamp != 2 || ha[1].Volume != 7 {
+		t.Errorf("second keeps timestamp %d and volume %v, want 2 and 7", ha[1].Timestamp, ha[1].Volume)
+	}
+}
+
+func TestRenko(t *testing.T) {
+	closes := []float64{100, 101, 102.5, 104.2, 103, 101, 99.9, 97.5}
+	candles := make([]types.Candle, len(closes))
+	for i, c := range closes {
+		candles[i] = types.Candle{Timestamp: int64(i), Close: c, Volume: 1}
+	}
+
+	bricks := Renko(candles, 2)
+	want := []struct {
+		timestamp   int64
+		open, close float64
+		volume      float64
+	}{
+		{2, 100, 102, 2}, // first up brick after a 2 point move
+		{3, 102, 104, 1}, // continuation needs one brick
+		{6, 102, 100, 3}, // reversal needs two bricks, from 104 to 100
+		{7, 100, 98, 1},
+	}
+	if len(bricks) != len(want) {
+		t.Fatalf("got %d bricks, want %d: %+v", len(bricks), len(want), bricks)
+	}
+	for i, w := range want {
+		b := bricks[i]
+		if b.Timestamp != w.timestamp || b.Open != w.open || b.Close != w.close || b.Volume != w.volume {
+			t.Errorf("brick %d = %+v, want %+v", i, b, w)
+		}
+	}
+
+	// A candle moving several bricks forms all of them
+	if bricks := Renko([]types.Candle{{Close: 100}, {Timestamp: 1, Close: 107}}, 2); len(bricks) != 3 {
+		t.Errorf("got %d bricks for a 7 point move, want 3", len(bricks))
+	}
+	if bricks := Renko(candles, 0); bricks != nil {
+		t.Errorf("zero brick size gave %d bricks, want none", len(bricks))
+	}
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -36,6 +37,26 @@ func ParseInterval(interval string) (time.Duration, error) {
 	}
 }
 
+// higherIntervals maps each interval to the one above it for trend checks
+var higherIntervals = map[string]string{
+	"1m":  "5m",
+	"3m":  "15m",
+	"5m":  "15m",
+	"15m": "1h",
+	"30m": "2h",
+	"1h":  "4h",
+	"2h":  "6h",
+	"4h":  "1d",
+	"6h":  "1d",
+	"12h": "1w",
+	"1d":  "1w",
+}
+
+// HigherInterval returns the timeframe above interval, or "" for the largest
+func HigherInterval(interval string) string {
+	return higherIntervals[interval]
+}
+
 type Candle struct {
 	Timestamp int64   `json:"timestamp"`
 	Open      float64 `json:"open"`
@@ -52,6 +73,9 @@ type CandleColor string
 const (
 	ColorGreen CandleColor = "green"
 	ColorRed   CandleColor = "red"
+	// ColorNeutral is a doji-like candle with no meaningful body. Color never
+	// returns it; strategies that classify bodies do.
+	ColorNeutral CandleColor = "neutral"
 )
 
 func (c *Candle) Color() CandleColor {
@@ -62,33 +86,144 @@ func (c *Candle) Color() CandleColor {
 }
 
 type Signal struct {
-	Symbol          string    `json:"symbol"`
-	Interval        string    `json:"interval"`
-	Pattern         string    `json:"pattern"`
-	Trend           string    `json:"trend"`
-	Price           float64   `json:"price"`
-	RSI             float64   `json:"rsi"`
-	EMA             float64   `json:"ema"`
-	Volume          float64   `json:"volume"`
-	Timestamp       time.Time `json:"timestamp"`
-	Candles         []Candle  `json:"candles"`
-	ConsecutiveCount int      `json:"consecutive_count"` // For consecutive candles pattern
+	Symbol           string                 `json:"symbol"`
+	Interval         string                 `json:"interval"`
+	Pattern          string                 `json:"pattern"`
+	Trend            string                 `json:"trend"`
+	Price            float64                `json:"price"`
+	RSI              float64                `json:"rsi"`
+	EMA              float64                `json:"ema"`
+	Volume           float64                `json:"volume"`
+	Timestamp        time.Time              `json:"timestamp"`
+	Candles          []Candle               `json:"candles"`
+	ConsecutiveCount int                    `json:"consecutive_count"`         // For consecutive candles pattern
+	VolumeMultiple   float64                `json:"volume_multiple,omitempty"` // For volume spikes, volume against its recent mean
+	Confidence       float64                `json:"confidence"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+	Score            SignalScore            `json:"score"`
+	Confluence       *Confluence            `json:"confluence,omitempty"`
+}
+
+// Confluence is the check of a signal against a higher timeframe trend
+type Confluence struct {
+	Interval string `json:"interval"`
+	Trend    string `json:"trend"` // "" when the trend could not be determined
+	Agrees   bool   `json:"agrees"`
+}
+
+// SignalScore rates a signal. Components range from 0 to 1 and Total is
+// their weighted average scaled to 0-100.
+type SignalScore struct {
+	Total     float64 `json:"total"`
+	Pattern   float64 `json:"pattern"`   // match confidence
+	Volume    float64 `json:"volume"`    // volume against its recent average
+	RSI       float64 `json:"rsi"`       // RSI stretched in the signal's favour
+	Trend     float64 `json:"trend"`     // agreement with the higher timeframe
+	Liquidity float64 `json:"liquidity"` // quote turnover
+}
+
+// NewSignal builds the signal for a match on symbol and interval. The price
+// and volume are those of the last pattern candle.
+func NewSignal(symbol, interval, pattern string, match *MatchResult, timestamp time.Time) Signal {
+	last := match.Candles[len(match.Candles)-1]
+
+	consecutiveCount := 0
+	if count, ok := match.Metadata["consecutive_count"].(int); ok {
+		consecutiveCount = count
+	}
+	volumeMultiple, _ := match.Metadata["volume_multiple"].(float64)
+
+	return Signal{
+		Symbol:           symbol,
+		Interval:         interval,
+		Pattern:          pattern,
+		Trend:            match.Direction,
+		Price:            last.Close,
+		Volume:           last.Volume,
+		Timestamp:        timestamp,
+		Candles:          match.Candles,
+		ConsecutiveCount: consecutiveCount,
+		VolumeMultiple:   volumeMultiple,
+		Confidence:       match.Confidence,
+		Metadata:         match.Metadata,
+	}
+}
+
+// Directions of a match, also used as Signal.Trend
+const (
+	Bullish = "bullish"
+	Bearish = "bearish"
+	Neutral = "neutral"
+)
+
+// MatchResult describes a pattern found at the end of a candle window
+type MatchResult struct {
+	Direction  string                 // Bullish, Bearish or Neutral
+	Confidence float64                // 0 to 1, how clear the pattern is
+	Candles    []Candle               // the candles that formed the pattern, never empty
+	Metadata   map[string]interface{} // strategy-specific details, e.g. "consecutive_count"
+}
+
+// MoverReport ranks every scanned symbol by its returns on one interval.
+// Returns are in percent, one per lookback.
+type MoverReport struct {
+	Interval         string    `json:"interval"`
+	Timestamp        time.Time `json:"timestamp"`
+	Lookbacks        []int     `json:"lookbacks"` // in candles
+	Benchmark        string    `json:"benchmark"`
+	BenchmarkReturns []float64 `json:"benchmark_returns"` // nil when the benchmark had too few candles
+	Symbols          int       `json:"symbols"`           // symbols ranked
+	Top              []Mover   `json:"top"`               // strongest first
+	Bottom           []Mover   `json:"bottom"`            // weakest first
+}
+
+// Mover is one symbol's place in a MoverReport
+type Mover struct {
+	Symbol  string    `json:"symbol"`
+	Returns []float64 `json:"returns"`
+	Excess  []float64 `json:"excess"` // return minus the benchmark's, nil without a benchmark
+	Score   float64   `json:"score"`  // average percentile of the returns against every symbol, 0-100
+}
+
+// Breadth summarises the last closed candle of every scanned symbol on one
+// interval. Percentages are of the symbols with enough history for them.
+type Breadth struct {
+	Interval    string    `json:"interval"`
+	Timestamp   time.Time `json:"timestamp"`
+	Symbols     int       `json:"symbols"`
+	GreenPct    float64   `json:"green_pct"`     // closed above their open
+	AboveEMA50  float64   `json:"above_ema50"`   // percent closing above EMA(50)
+	AboveEMA200 float64   `json:"above_ema200"`  // percent closing above EMA(200)
+	HighLowBars int       `json:"high_low_bars"` // N for the new highs and lows
+	NewHighs    int       `json:"new_highs"`     // high above the previous N candles
+	NewLows     int       `json:"new_lows"`      // low below the previous N candles
+	Advancing   int       `json:"advancing"`     // closed above the previous close
+	Declining   int       `json:"declining"`     // closed below the previous close
+}
+
+// AdvanceDecline is Advancing over Declining; ok is false without decliners
+func (b Breadth) AdvanceDecline() (ratio float64, ok bool) {
+	if b.Declining == 0 {
+		return 0, false
+	}
+	return float64(b.Advancing) / float64(b.Declining), true
 }
 
 type MarketDataProvider interface {
-	GetSymbols() ([]string, error)
-	GetCandles(symbol, interval string, limit int, endTime int64) ([]Candle, error)
+	GetSymbols(ctx context.Context) ([]string, error)
+	GetCandles(ctx context.Context, symbol, interval string, limit int, endTime int64) ([]Candle, error)
 }
 
 type PatternMatcher interface {
-	Match(candles []Candle) (bool, error)
+	Match(candles []Candle) (*MatchResult, error) // nil when the pattern is not at the end of candles
 	GetName() string
 	GetDescription() string
 	GetRequiredCandles() int
-	GetMetadata(candles []Candle) map[string]interface{} // Get additional match data
 }
 
 type NotificationSender interface {
-	SendSignals(signals []Signal) error
-	SendMessage(message string) error
+	SendSignals(ctx context.Context, signals []Signal) error
+	SendMessage(ctx context.Context, message string) error
+	SendMovers(ctx context.Context, report MoverReport) error
+	SendBreadth(ctx context.Context, breadth Breadth) error
 }
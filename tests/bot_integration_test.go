@@ -1,11 +1,14 @@
 package tests
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/letieu/trade-bot/internal/bot"
 	"github.com/letieu/trade-bot/internal/config"
+	"github.com/letieu/trade-bot/internal/indicators"
 	"github.com/letieu/trade-bot/internal/types"
 )
 
@@ -14,28 +17,107 @@ type MockProvider struct {
 	candles []types.Candle
 }
 
-func (m *MockProvider) GetSymbols() ([]string, error) {
+func (m *MockProvider) GetSymbols(ctx context.Context) ([]string, error) {
 	return []string{"BTCUSDT"}, nil
 }
 
-func (m *MockProvider) GetCandles(symbol, interval string, limit int, endTime int64) ([]types.Candle, error) {
+func (m *MockProvider) GetCandles(ctx context.Context, symbol, interval string, limit int, endTime int64) ([]types.Candle, error) {
 	return m.candles, nil
 }
 
 // MockSender implements types.NotificationSender
 type MockSender struct {
 	Signals []types.Signal
+	Movers  []types.MoverReport
+	Breadth []types.Breadth
+
+	MoversErr  error // returned by SendMovers after recording the report
+	BreadthErr error // returned by SendBreadth after recording the report
 }
 
-func (m *MockSender) SendSignals(signals []types.Signal) error {
+func (m *MockSender) SendSignals(ctx context.Context, signals []types.Signal) error {
 	m.Signals = append(m.Signals, signals...)
 	return nil
 }
 
-func (m *MockSender) SendMessage(message string) error {
+func (m *MockSender) SendMessage(ctx context.Context, message string) error {
 	return nil
 }
 
+func (m *MockSender) SendMovers(ctx context.Context, report types.MoverReport) error {
+	m.Movers = append(m.Movers, report)
+	return m.MoversErr
+}
+
+func (m *MockSender) SendBreadth(ctx context.Context, breadth types.Breadth) error {
+	m.Breadth = append(m.Breadth, breadth)
+	return m.BreadthErr
+}
+
+// newBot builds a bot from cfg, failing the test on a bad config
+func newBot(t *testing.T, cfg *config.Config, provider types.MarketDataProvider, sender types.NotificationSender) *bot.Bot {
+	t.Helper()
+	tradingBot, err := bot.NewBotWithDeps(cfg, provider, sender)
+	if err != nil {
+		t.Fatalf("NewBotWithDeps() error = %v", err)
+	}
+	return tradingBot
+}
+
+func TestNewBotWithDeps_InvalidConfig(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name string
+		cfg  config.Config
+	}{
+		{
+			name: "unknown strategy",
+			cfg:  config.Config{Strategies: []config.StrategyConfig{{Name: "nope"}}},
+		},
+		{
+			name: "no strategy enabled",
+			cfg:  config.Config{Strategies: []config.StrategyConfig{{Name: "reversal", Enabled: &disabled}}},
+		},
+		{
+			name: "confluence not on a longer interval",
+			cfg: config.Config{Strategies: []config.StrategyConfig{{
+				Name:       "reversal",
+				Confluence: config.ConfluenceConfig{Mode: "tag", Interval: "1h"},
+			}}},
+		},
+		{
+			name: "relative strength lookback below 1",
+			cfg:  config.Config{RelativeStrength: config.RelativeStrengthConfig{Enabled: true, Lookbacks: []int{0}}},
+		},
+		{
+			name: "relative strength on an interval that is never scanned",
+			cfg:  config.Config{RelativeStrength: config.RelativeStrengthConfig{Enabled: true, Intervals: []string{"1d"}}},
+		},
+		{
+			name: "breadth highLowBars below 1",
+			cfg:  config.Config{Breadth: config.BreadthConfig{Enabled: true, HighLowBars: -1}},
+		},
+		{
+			name: "breadth on an interval that is never scanned",
+			cfg:  config.Config{Breadth: config.BreadthConfig{Enabled: true, Intervals: []string{"1d"}}},
+		},
+		{
+			name: "negative scoring weight",
+			cfg:  config.Config{Scoring: config.ScoringConfig{Weights: config.ScoreWeights{Pattern: 1, RSI: -1}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.Bot.EnabledIntervals = []string{"1h", "4h"}
+			if _, err := bot.NewBotWithDeps(&cfg, &MockProvider{}, &MockSender{}); err == nil {
+				t.Error("NewBotWithDeps() error = nil, want an invalid config error")
+			}
+		})
+	}
+}
+
 func TestBot_Scan_Integration(t *testing.T) {
 	// Setup Mock Data: 3 Red + 1 Green + 1 Forming (Red)
 	// Chronological Order: Oldest -> Newest
@@ -65,11 +147,11 @@ func TestBot_Scan_Integration(t *testing.T) {
 	}
 
 	// Create Bot with Mocks
-	tradingBot := bot.NewBotWithDeps(cfg, mockProvider, mockSender)
+	tradingBot := newBot(t, cfg, mockProvider, mockSender)
 
 	// Run Scan
 	// Since RunOnce is true, Start() should call scan() once and return.
-	if err := tradingBot.Start(); err != nil {
+	if err := tradingBot.Start(context.Background()); err != nil {
 		t.Fatalf("Bot Start failed: %v", err)
 	}
 
@@ -131,9 +213,9 @@ func TestBot_DoesNotRemoveClosedCandle(t *testing.T) {
 		},
 	}
 
-	tradingBot := bot.NewBotWithDeps(cfg, mockProvider, mockSender)
+	tradingBot := newBot(t, cfg, mockProvider, mockSender)
 
-	if err := tradingBot.Start(); err != nil {
+	if err := tradingBot.Start(context.Background()); err != nil {
 		t.Fatalf("Bot Start failed: %v", err)
 	}
 
@@ -174,3 +256,504 @@ func TestBot_DoesNotRemoveClosedCandle(t *testing.T) {
 		t.Errorf("Found 'TĂNG GIẢM LIÊN TỤC' signal. This implies the last Green candle was stripped and the bot saw the previous Reds!")
 	}
 }
+
+func TestBot_StopsOnContextCancel(t *testing.T) {
+	cfg := &config.Config{
+		Bot: config.BotConfig{
+			EnabledIntervals: []string{"1h", "4h"},
+			MaxConcurrency:   1,
+			BatchSize:        1,
+			Frontend:         "console",
+		},
+	}
+	tradingBot := newBot(t, cfg, &MockProvider{}, &MockSender{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- tradingBot.Start(ctx) }()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Start() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Start() did not return after the context was cancelled")
+	}
+}
+
+// cancellingProvider cancels the scan context while serving candles, as a
+// SIGTERM arriving mid-scan would
+type cancellingProvider struct {
+	MockProvider
+	cancel context.CancelFunc
+}
+
+func (p *cancellingProvider) GetCandles(ctx context.Context, symbol, interval string, limit int, endTime int64) ([]types.Candle, error) {
+	p.cancel()
+	return p.candles, nil
+}
+
+// ctxSender records whether the context it was given was still usable
+type ctxSender struct {
+	MockSender
+	sendErr error
+}
+
+func (s *ctxSender) SendSignals(ctx context.Context, signals []types.Signal) error {
+	s.sendErr = ctx.Err()
+	return s.MockSender.SendSignals(ctx, signals)
+}
+
+func TestBot_FlushesSignalsAfterCancel(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Hour)
+	candles := []types.Candle{
+		{Timestamp: now.Add(-5 * time.Hour).UnixMilli(), Open: 110, Close: 100},
+		{Timestamp: now.Add(-4 * time.Hour).UnixMilli(), Open: 100, Close: 90},
+		{Timestamp: now.Add(-3 * time.Hour).UnixMilli(), Open: 90, Close: 80},
+		{Timestamp: now.Add(-2 * time.Hour).UnixMilli(), Open: 80, Close: 70},
+		{Timestamp: now.Add(-1 * time.Hour).UnixMilli(), Open: 70, Close: 75},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	provider := &cancellingProvider{MockProvider: MockProvider{candles: candles}, cancel: cancel}
+	sender := &ctxSender{}
+
+	cfg := &config.Config{
+		Bot: config.BotConfig{
+			EnabledIntervals: []string{"1h"},
+			MaxConcurrency:   1,
+			BatchSize:        1,
+			Frontend:         "console",
+			RunOnce:          true,
+		},
+	}
+
+	if err := newBot(t, cfg, provider, sender).Start(ctx); err != nil {
+		t.Fatalf("Bot Start failed: %v", err)
+	}
+
+	if len(sender.Signals) == 0 {
+		t.Fatal("expected signals found before the shutdown to be sent")
+	}
+	if sender.sendErr != nil {
+		t.Errorf("signals were sent on a cancelled context: %v", sender.sendErr)
+	}
+}
+
+func TestBot_FillsIndicators(t *testing.T) {
+	// A long uptrend that ends with three red candles and a green reversal
+	now := time.Now().UTC().Truncate(time.Hour)
+	var candles []types.Candle
+	price := 100.0
+	for i := 40; i >= 1; i-- {
+		open, close := price, price+1
+		switch i {
+		case 4, 3, 2:
+			close = price - 2
+		}
+		candles = append(candles, types.Candle{
+			Timestamp: now.Add(-time.Duration(i) * time.Hour).UnixMilli(),
+			Open:      open,
+			High:      open + 3,
+			Low:       close - 3,
+			Close:     close,
+			Volume:    1000,
+		})
+		price = close
+	}
+
+	mockSender := &MockSender{}
+	cfg := &config.Config{
+		Bot: config.BotConfig{
+			EnabledIntervals: []string{"1h"},
+			MaxConcurrency:   1,
+			BatchSize:        1,
+			Frontend:         "console",
+			RunOnce:          true,
+		},
+	}
+
+	if err := newBot(t, cfg, &MockProvider{candles: candles}, mockSender).Start(context.Background()); err != nil {
+		t.Fatalf("Bot Start failed: %v", err)
+	}
+	if len(mockSender.Signals) == 0 {
+		t.Fatal("Expected a reversal signal")
+	}
+
+	rsi := indicators.RSISeries(candles, 14)
+	ema := indicators.EMASeries(candles, 20)
+	last := len(candles) - 1
+	for _, signal := range mockSender.Signals {
+		if signal.RSI != rsi[last] || signal.RSI == 0 {
+			t.Errorf("%s: RSI = %v, want %v", signal.Pattern, signal.RSI, rsi[last])
+		}
+		if signal.EMA != ema[last] || signal.EMA == 0 {
+			t.Errorf("%s: EMA = %v, want %v", signal.Pattern, signal.EMA, ema[last])
+		}
+	}
+}
+
+func TestBot_StrategyIntervals(t *testing.T) {
+	// The reversal matches on these candles, but is configured for 4h only
+	now := time.Now().UTC().Truncate(time.Hour)
+	candles := []types.Candle{
+		{Timestamp: now.Add(-4 * time.Hour).UnixMilli(), Open: 100, Close: 90},
+		{Timestamp: now.Add(-3 * time.Hour).UnixMilli(), Open: 90, Close: 80},
+		{Timestamp: now.Add(-2 * time.Hour).UnixMilli(), Open: 80, Close: 70},
+		{Timestamp: now.Add(-1 * time.Hour).UnixMilli(), Open: 70, Close: 75},
+	}
+
+	for _, tt := range []struct {
+		intervals   []string
+		wantSignals int
+	}{
+		{intervals: []string{"4h"}, wantSignals: 0},
+		{intervals: []string{"1h", "4h"}, wantSignals: 1},
+	} {
+		mockSender := &MockSender{}
+		cfg := &config.Config{
+			Bot: config.BotConfig{
+				EnabledIntervals: []string{"1h"},
+				MaxConcurrency:   1,
+				BatchSize:        1,
+				Frontend:         "console",
+				RunOnce:          true,
+			},
+			Strategies: []config.StrategyConfig{
+				{Name: "reversal", Intervals: tt.intervals, Params: map[string]interface{}{"count": 3}},
+			},
+		}
+
+		if err := newBot(t, cfg, &MockProvider{candles: candles}, mockSender).Start(context.Background()); err != nil {
+			t.Fatalf("Bot Start failed: %v", err)
+		}
+		if len(mockSender.Signals) != tt.wantSignals {
+			t.Errorf("intervals %v: got %d signals, want %d", tt.intervals, len(mockSender.Signals), tt.wantSignals)
+		}
+	}
+}
+
+func TestBot_ScoresSignals(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Hour)
+	candles := []types.Candle{
+		{Timestamp: now.Add(-4 * time.Hour).UnixMilli(), Open: 100, Close: 90, Volume: 1000},
+		{Timestamp: now.Add(-3 * time.Hour).UnixMilli(), Open: 90, Close: 80, Volume: 1000},
+		{Timestamp: now.Add(-2 * time.Hour).UnixMilli(), Open: 80, Close: 70, Volume: 1000},
+		{Timestamp: now.Add(-1 * time.Hour).UnixMilli(), Open: 70, Close: 75, Volume: 3000},
+	}
+
+	for _, tt := range []struct {
+		minScore    float64
+		wantSignals int
+	}{
+		{minScore: 0, wantSignals: 1},
+		{minScore: 99, wantSignals: 0},
+	} {
+		mockSender := &MockSender{}
+		cfg := &config.Config{
+			Bot: config.BotConfig{
+				EnabledIntervals: []string{"1h"},
+				MaxConcurrency:   1,
+				BatchSize:        1,
+				Frontend:         "console",
+				RunOnce:          true,
+			},
+			Scoring: config.ScoringConfig{MinScore: tt.minScore},
+		}
+
+		if err := newBot(t, cfg, &MockProvider{candles: candles}, mockSender).Start(context.Background()); err != nil {
+			t.Fatalf("Bot Start failed: %v", err)
+		}
+		if len(mockSender.Signals) != tt.wantSignals {
+			t.Fatalf("minScore %v: got %d signals, want %d", tt.minScore, len(mockSender.Signals), tt.wantSignals)
+		}
+
+		for _, signal := range mockSender.Signals {
+			// Volume triples on the reversal candle
+			if signal.Score.Volume != 1 || signal.Score.Pattern != signal.Confidence || signal.Score.Total <= 0 {
+				t.Errorf("unexpected score %+v", signal.Score)
+			}
+		}
+	}
+}
+
+// intervalProvider serves different candles per interval
+type intervalProvider struct {
+	MockProvider
+	byInterval map[string][]types.Candle
+}
+
+func (p *intervalProvider) GetCandles(ctx context.Context, symbol, interval string, limit int, endTime int64) ([]types.Candle, error) {
+	return p.byInterval[interval], nil
+}
+
+func TestBot_Confluence(t *testing.T) {
+	// A bullish reversal on 1h
+	now := time.Now().UTC().Truncate(time.Hour)
+	hourly := []types.Candle{
+		{Timestamp: now.Add(-4 * time.Hour).UnixMilli(), Open: 100, Close: 90},
+		{Timestamp: now.Add(-3 * time.Hour).UnixMilli(), Open: 90, Close: 80},
+		{Timestamp: now.Add(-2 * time.Hour).UnixMilli(), Open: 80, Close: 70},
+		{Timestamp: now.Add(-1 * time.Hour).UnixMilli(), Open: 70, Close: 75},
+	}
+
+	// Closed 4h candles steadily rising or falling, putting the last close above
+	// or below EMA50, then a forming candle closing at forming when it is set
+	current4h := time.Now().UTC().Truncate(4 * time.Hour)
+	trending := func(step, forming float64) []types.Candle {
+		candles := make([]types.Candle, 60)
+		for i := range candles {
+			price := 1000 + step*float64(i)
+			candles[i] = types.Candle{Timestamp: current4h.Add(time.Duration(i-60) * 4 * time.Hour).UnixMilli(), Open: price - step, Close: price}
+		}
+		if forming != 0 {
+			last := candles[len(candles)-1].Close
+			candles = append(candles, types.Candle{Timestamp: current4h.UnixMilli(), Open: last, Close: forming})
+		}
+		return candles
+	}
+
+	tests := []struct {
+		name        string
+		mode        string
+		step        float64
+		forming     float64
+		wantSignals int
+		wantAgrees  bool
+	}{
+		{name: "require, 4h agrees", mode: "require", step: 1, wantSignals: 1, wantAgrees: true},
+		{name: "require, 4h disagrees", mode: "require", step: -1, wantSignals: 0},
+		{name: "tag, 4h disagrees", mode: "tag", step: -1, wantSignals: 1, wantAgrees: false},
+		{name: "require, only the forming 4h disagrees", mode: "require", step: 1, forming: 500, wantSignals: 1, wantAgrees: true},
+		{name: "require, only the forming 4h agrees", mode: "require", step: -1, forming: 2000, wantSignals: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSender := &MockSender{}
+			cfg := &config.Config{
+				Bot: config.BotConfig{
+					EnabledIntervals: []string{"1h"},
+					MaxConcurrency:   1,
+					BatchSize:        1,
+					Frontend:         "console",
+					RunOnce:          true,
+				},
+				Strategies: []config.StrategyConfig{
+					{Name: "reversal", Confluence: config.ConfluenceConfig{Mode: tt.mode}},
+				},
+			}
+			provider := &intervalProvider{byInterval: map[string][]types.Candle{"1h": hourly, "4h": trending(tt.step, tt.forming)}}
+
+			if err := newBot(t, cfg, provider, mockSender).Start(context.Background()); err != nil {
+				t.Fatalf("Bot Start failed: %v", err)
+			}
+			if len(mockSender.Signals) != tt.wantSignals {
+				t.Fatalf("got %d signals, want %d", len(mockSender.Signals), tt.wantSignals)
+			}
+			if tt.wantSignals == 0 {
+				return
+			}
+
+			c := mockSender.Signals[0].Confluence
+			if c == nil || c.Interval != "4h" || c.Agrees != tt.wantAgrees {
+				t.Errorf("Confluence = %+v, want 4h with agrees %v", c, tt.wantAgrees)
+			}
+		})
+	}
+}
+
+// symbolProvider serves different candles per symbol
+type symbolProvider struct {
+	bySymbol map[string][]types.Candle
+}
+
+func (p *symbolProvider) GetSymbols(ctx context.Context) ([]string, error) {
+	var symbols []string
+	for symbol := range p.bySymbol {
+		symbols = append(symbols, symbol)
+	}
+	return symbols, nil
+}
+
+func (p *symbolProvider) GetCandles(ctx context.Context, symbol, interval string, limit int, endTime int64) ([]types.Candle, error) {
+	return p.bySymbol[symbol], nil
+}
+
+func TestBot_RelativeStrength(t *testing.T) {
+	// Closed hourly candles drifting by step per candle
+	now := time.Now().UTC().Truncate(time.Hour)
+	drift := func(step float64) []types.Candle {
+		candles := make([]types.Candle, 30)
+		for i := range candles {
+			price := 100 + step*float64(i)
+			candles[i] = types.Candle{Timestamp: now.Add(time.Duration(i-30) * time.Hour).UnixMilli(), Open: price, High: price, Low: price, Close: price}
+		}
+		return candles
+	}
+	provider := &symbolProvider{bySymbol: map[string][]types.Candle{
+		"BTCUSDT": drift(0.5),
+		"SOLUSDT": drift(2),
+		"ETHUSDT": drift(0),
+		"XRPUSDT": drift(-1),
+	}}
+
+	mockSender := &MockSender{}
+	cfg := &config.Config{
+		Bot: config.BotConfig{
+			EnabledIntervals: []string{"1h", "4h"},
+			MaxConcurrency:   2,
+			BatchSize:        2,
+			Frontend:         "console",
+			RunOnce:          true,
+		},
+		RelativeStrength: config.RelativeStrengthConfig{
+			Enabled:   true,
+			Intervals: []string{"1h"},
+			Lookbacks: []int{1, 24},
+			Top:       1,
+		},
+	}
+
+	if err := newBot(t, cfg, provider, mockSender).Start(context.Background()); err != nil {
+		t.Fatalf("Bot Start failed: %v", err)
+	}
+
+	if len(mockSender.Movers) != 1 {
+		t.Fatalf("got %d mover reports, want 1 for the 1h interval only", len(mockSender.Movers))
+	}
+	report := mockSender.Movers[0]
+	if report.Interval != "1h" || report.Symbols != 4 || report.Benchmark != "BTCUSDT" {
+		t.Errorf("report = %s with %d symbols against %s, want 1h with 4 against BTCUSDT", report.Interval, report.Symbols, report.Benchmark)
+	}
+	if len(report.Top) != 1 || report.Top[0].Symbol != "SOLUSDT" {
+		t.Errorf("top = %+v, want SOLUSDT", report.Top)
+	}
+	if len(report.Bottom) != 1 || report.Bottom[0].Symbol != "XRPUSDT" {
+		t.Errorf("bottom = %+v, want XRPUSDT", report.Bottom)
+	}
+}
+
+func TestBot_Breadth(t *testing.T) {
+	// Closed hourly candles, each opening at the previous close and moving by step
+	now := time.Now().UTC().Truncate(time.Hour)
+	drift := func(step float64) []types.Candle {
+		candles := make([]types.Candle, 30)
+		for i := range candles {
+			open, close := 100+step*float64(i), 100+step*float64(i+1)
+			candles[i] = types.Candle{
+				Timestamp: now.Add(time.Duration(i-30) * time.Hour).UnixMilli(),
+				Open:      open,
+				High:      max(open, close),
+				Low:       min(open, close),
+				Close:     close,
+			}
+		}
+		return candles
+	}
+	provider := &symbolProvider{bySymbol: map[string][]types.Candle{
+		"BTCUSDT": drift(1),
+		"ETHUSDT": drift(0),
+		"XRPUSDT": drift(-1),
+	}}
+
+	mockSender := &MockSender{}
+	cfg := &config.Config{
+		Bot: config.BotConfig{
+			EnabledIntervals: []string{"1h", "4h"},
+			MaxConcurrency:   2,
+			BatchSize:        2,
+			Frontend:         "console",
+			RunOnce:          true,
+		},
+		Breadth: config.BreadthConfig{
+			Enabled:   true,
+			Intervals: []string{"1h"},
+		},
+	}
+
+	if err := newBot(t, cfg, provider, mockSender).Start(context.Background()); err != nil {
+		t.Fatalf("Bot Start failed: %v", err)
+	}
+
+	if len(mockSender.Breadth) != 1 {
+		t.Fatalf("got %d breadth reports, want 1 for the 1h interval only", len(mockSender.Breadth))
+	}
+	breadth := mockSender.Breadth[0]
+	if breadth.Interval != "1h" || breadth.Symbols != 3 || breadth.HighLowBars != 20 {
+		t.Errorf("breadth = %s with %d symbols over %d bars, want 1h with 3 over 20", breadth.Interval, breadth.Symbols, breadth.HighLowBars)
+	}
+	if breadth.NewHighs != 1 || breadth.NewLows != 1 {
+		t.Errorf("new highs/lows = %d/%d, want 1/1", breadth.NewHighs, breadth.NewLows)
+	}
+	if breadth.Advancing != 1 || breadth.Declining != 1 {
+		t.Errorf("advancing/declining = %d/%d, want 1/1", breadth.Advancing, breadth.Declining)
+	}
+}
+
+func TestBot_ReportFailureStillSendsSignals(t *testing.T) {
+	// 3 Red + 1 Green + 1 Forming on every symbol: a reversal signal each
+	now := time.Now().Truncate(time.Hour)
+	candles := []types.Candle{
+		{Timestamp: now.Add(-4 * time.Hour).UnixMilli(), Open: 100, High: 100, Low: 90, Close: 90},
+		{Timestamp: now.Add(-3 * time.Hour).UnixMilli(), Open: 90, High: 90, Low: 80, Close: 80},
+		{Timestamp: now.Add(-2 * time.Hour).UnixMilli(), Open: 80, High: 80, Low: 70, Close: 70},
+		{Timestamp: now.Add(-1 * time.Hour).UnixMilli(), Open: 70, High: 75, Low: 70, Close: 75},
+		{Timestamp: now.UnixMilli(), Open: 75, High: 75, Low: 70, Close: 70},
+	}
+	failure := errors.New("telegram is down")
+
+	tests := []struct {
+		name    string
+		sender  *MockSender
+		cfg     config.Config
+		reports func(*MockSender) int
+	}{
+		{
+			name:    "movers",
+			sender:  &MockSender{MoversErr: failure},
+			cfg:     config.Config{RelativeStrength: config.RelativeStrengthConfig{Enabled: true, Lookbacks: []int{1}, Top: 1}},
+			reports: func(m *MockSender) int { return len(m.Movers) },
+		},
+		{
+			name:    "breadth",
+			sender:  &MockSender{BreadthErr: failure},
+			cfg:     config.Config{Breadth: config.BreadthConfig{Enabled: true, HighLowBars: 2}},
+			reports: func(m *MockSender) int { return len(m.Breadth) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &symbolProvider{bySymbol: map[string][]types.Candle{
+				"BTCUSDT": candles,
+				"ETHUSDT": candles,
+			}}
+			cfg := tt.cfg
+			cfg.Bot = config.BotConfig{
+				EnabledIntervals: []string{"1h"},
+				MaxConcurrency:   1,
+				BatchSize:        2,
+				Frontend:         "console",
+				RunOnce:          true,
+			}
+
+			if err := newBot(t, &cfg, provider, tt.sender).Start(context.Background()); err != nil {
+				t.Fatalf("Bot Start failed: %v", err)
+			}
+
+			if got := tt.reports(tt.sender); got != 1 {
+				t.Fatalf("got %d %s reports, want the failing one", got, tt.name)
+			}
+			if len(tt.sender.Signals) != 2 {
+				t.Errorf("got %d signals, want both sent despite the failed report", len(tt.sender.Signals))
+			}
+		})
+	}
+}